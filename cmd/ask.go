@@ -0,0 +1,283 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of K9s
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"github.com/quentincherifi/c9s/internal/ai"
+	"github.com/quentincherifi/c9s/internal/client"
+	"github.com/quentincherifi/c9s/internal/config"
+	"github.com/quentincherifi/c9s/internal/dao"
+	"github.com/quentincherifi/c9s/internal/slogs"
+	"github.com/quentincherifi/c9s/internal/watch"
+	"github.com/spf13/cobra"
+)
+
+const (
+	askOutputMarkdown = "markdown"
+	askOutputJSON     = "json"
+)
+
+// askFlags tracks the ask command flags.
+type askFlags struct {
+	resource string
+	output   string
+	model    string
+}
+
+// askResult represents an answer in JSON output.
+type askResult struct {
+	Context   string    `json:"context,omitempty"`
+	Cluster   string    `json:"cluster,omitempty"`
+	Namespace string    `json:"namespace,omitempty"`
+	Resource  string    `json:"resource,omitempty"`
+	Question  string    `json:"question"`
+	Answer    string    `json:"answer"`
+	Model     string    `json:"model"`
+	Runbooks  []string  `json:"runbooks,omitempty"`
+	Usage     *ai.Usage `json:"usage,omitempty"`
+}
+
+func askCmd() *cobra.Command {
+	var flags askFlags
+	command := cobra.Command{
+		Use:   "ask [question]",
+		Short: "Ask Claude about the cluster and print the answer",
+		Long: `Ask Claude a question about the cluster without starting the UI.
+The answer is printed as Markdown or JSON so it may be used in runbooks and CI.`,
+		Example: `  c9s ask --context prod -n fred -r deploy/blee "why is this rollout stuck?"
+  c9s ask -r po/fred-123 -o json "what is wrong with this pod?"
+  c9s ask -m fast "what does a CrashLoopBackOff mean?"`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runAsk(cmd.Context(), &flags, strings.Join(args, " "))
+		},
+	}
+
+	command.Flags().StringVar(
+		k8sFlags.KubeConfig,
+		"kubeconfig",
+		"",
+		"Path to the kubeconfig file to use for CLI requests",
+	)
+	command.Flags().StringVar(
+		k8sFlags.Context,
+		"context",
+		"",
+		"The name of the kubeconfig context to use",
+	)
+	command.Flags().StringVarP(
+		k8sFlags.Namespace,
+		"namespace",
+		"n",
+		"",
+		"If present, the namespace scope for this CLI request",
+	)
+	command.Flags().StringVarP(
+		&flags.resource,
+		"resource",
+		"r",
+		"",
+		"The resource to ask about as kind/name, i.e. deploy/fred",
+	)
+	command.Flags().StringVarP(
+		&flags.output,
+		"output",
+		"o",
+		askOutputMarkdown,
+		"Output format. One of: markdown|json",
+	)
+	command.Flags().StringVarP(
+		&flags.model,
+		"model",
+		"m",
+		"",
+		"The model alias or name to ask, i.e. fast",
+	)
+
+	return &command
+}
+
+func runAsk(ctx context.Context, flags *askFlags, question string) error {
+	if flags.output != askOutputMarkdown && flags.output != askOutputJSON {
+		return fmt.Errorf("invalid output format %q. Must be one of markdown|json", flags.output)
+	}
+	if err := config.InitLocs(); err != nil {
+		return err
+	}
+	logFile, err := initLogs()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = logFile.Close()
+	}()
+
+	cfg, err := loadConfiguration()
+	if err != nil {
+		slog.Warn("Fail to load global/context configuration", slogs.Error, err)
+	}
+	policy := cfg.K9s.AIPolicy()
+	if policy.IsDisabled() {
+		return ai.ErrPolicyDisabled
+	}
+	aiCfg := cfg.K9s.ActiveAI()
+	if flags.model != "" && flags.model != config.DefaultAIModelAlias {
+		aiCfg.ApplyModel(aiCfg.ResolveModel(flags.model))
+	}
+	apiKey, err := aiCfg.GetAPIKey()
+	if err != nil {
+		return err
+	}
+	if apiKey == "" && aiCfg.RequiresAPIKey() {
+		return errors.New("API key not configured. Use `c9s` then `:claude set-key <your-api-key>` to set it")
+	}
+
+	k := ai.K8sContext{
+		ContextName: cfg.ActiveContextName(),
+		Namespace:   cfg.ActiveNamespace(),
+	}
+	var (
+		f     *watch.Factory
+		alias *dao.Alias
+	)
+	if conn := cfg.GetConnection(); conn != nil && conn.ConnectionOK() {
+		if n, err := conn.Config().CurrentClusterName(); err == nil {
+			k.ClusterName = n
+		}
+		f = watch.NewFactory(conn)
+		f.Start(k.Namespace)
+		defer f.Terminate()
+		alias = dao.NewAlias(f)
+		if _, err := alias.Ensure(cfg.ContextAliasesPath()); err != nil {
+			return err
+		}
+	}
+
+	if flags.resource != "" {
+		if f == nil {
+			return fmt.Errorf("k8s connection failed for context: %s", k.ContextName)
+		}
+		if err := askResource(ctx, f, alias, &k, flags.resource, aiCfg); err != nil {
+			return err
+		}
+	}
+
+	if dirs := aiCfg.Runbooks.GetDirs(); len(dirs) > 0 {
+		idx, err := ai.LoadRunbooks(dirs)
+		if err != nil {
+			slog.Warn("Unable to index some runbooks", slogs.Error, err)
+		}
+		k.Runbooks = idx.Search(question, aiCfg.Runbooks.GetMaxExcerpts(), aiCfg.Runbooks.GetMaxSize())
+	}
+
+	c, err := ai.NewConfigClient(aiCfg, apiKey)
+	if err != nil {
+		return err
+	}
+	c.SetRetryFunc(func(attempt, maxRetries int, wait time.Duration, err error) {
+		slog.Warn("AI request failed, retrying",
+			slogs.Error, err,
+			slogs.Retry, attempt,
+			slogs.MaxRetries, maxRetries,
+			slogs.Duration, wait,
+		)
+	})
+	var tb *ai.Toolbox
+	if f != nil {
+		tb = ai.NewK8sToolbox(f, alias)
+		tb.Restrict(policy)
+		k.ToolsEnabled = true
+	}
+	k.Restrict(policy)
+	k.Instructions = aiCfg.SystemPrompt
+	system, err := ai.BuildSystemPrompt(&k)
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt)
+	defer stop()
+
+	var streamFn ai.StreamFunc
+	if flags.output == askOutputMarkdown {
+		streamFn = func(s string) {
+			_, _ = fmt.Fprint(out, s)
+		}
+	}
+	turn, err := c.Converse(ctx, system, []ai.Message{{Role: "user", Content: question}}, tb, streamFn, nil)
+	if err != nil {
+		return errors.New(ai.Describe(err))
+	}
+	if flags.output == askOutputMarkdown {
+		_, _ = fmt.Fprintln(out)
+		return nil
+	}
+
+	return printAskResult(out, askResult{
+		Context:   k.ContextName,
+		Cluster:   k.ClusterName,
+		Namespace: k.Namespace,
+		Resource:  k.SelectedResource,
+		Question:  question,
+		Answer:    turn[len(turn)-1].Text(),
+		Model:     aiCfg.GetModel(),
+		Runbooks:  ai.ExcerptFiles(k.Runbooks),
+		Usage:     turn[len(turn)-1].Usage,
+	})
+}
+
+// askResource loads the given kind/name resource manifest, events and
+// dependency tree.
+func askResource(ctx context.Context, f *watch.Factory, r ai.Resolver, k *ai.K8sContext, res string, cfg *config.AI) error {
+	kind, name, err := parseResource(res)
+	if err != nil {
+		return err
+	}
+	gvr, err := ai.ResolveKind(r, kind)
+	if err != nil {
+		return err
+	}
+	path := name
+	if ok, err := dao.MetaAccess.IsNamespaced(gvr); err == nil && ok {
+		path = client.FQN(k.Namespace, name)
+	}
+	k.ResourceType, k.SelectedResource = gvr.String(), path
+	if _, err := k.LoadResource(f, gvr, path, cfg); err != nil {
+		return err
+	}
+
+	if err := k.LoadTree(ctx, f, gvr, path, cfg); err != nil {
+		slog.Warn("Unable to gather dependency tree", slogs.FQN, path, slogs.Error, err)
+	}
+
+	return nil
+}
+
+// parseResource splits a kind/name resource specification.
+func parseResource(s string) (kind, name string, err error) {
+	kind, name, ok := strings.Cut(s, "/")
+	if !ok || kind == "" || name == "" || strings.Contains(name, "/") {
+		return "", "", fmt.Errorf("invalid resource %q. Expecting kind/name", s)
+	}
+
+	return kind, name, nil
+}
+
+func printAskResult(w io.Writer, r askResult) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	return enc.Encode(r)
+}
@@ -0,0 +1,77 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of K9s
+
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/quentincherifi/c9s/internal/ai"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_parseResource(t *testing.T) {
+	uu := map[string]struct {
+		res, kind, name string
+		err             bool
+	}{
+		"happy": {
+			res:  "deploy/fred",
+			kind: "deploy",
+			name: "fred",
+		},
+		"no-kind": {
+			res: "/fred",
+			err: true,
+		},
+		"no-name": {
+			res: "deploy/",
+			err: true,
+		},
+		"name-only": {
+			res: "fred",
+			err: true,
+		},
+		"ns-name": {
+			res: "deploy/default/fred",
+			err: true,
+		},
+	}
+
+	for k := range uu {
+		u := uu[k]
+		t.Run(k, func(t *testing.T) {
+			kind, name, err := parseResource(u.res)
+			if u.err {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, u.kind, kind)
+			assert.Equal(t, u.name, name)
+		})
+	}
+}
+
+func Test_printAskResult(t *testing.T) {
+	var w bytes.Buffer
+	require.NoError(t, printAskResult(&w, askResult{
+		Context:  "ctx1",
+		Resource: "default/fred",
+		Question: "Why?",
+		Answer:   "Because.",
+		Model:    "claude",
+		Usage:    &ai.Usage{InputTokens: 10, OutputTokens: 2},
+	}))
+
+	assert.JSONEq(t, `{
+  "context": "ctx1",
+  "resource": "default/fred",
+  "question": "Why?",
+  "answer": "Because.",
+  "model": "claude",
+  "usage": {"input_tokens": 10, "output_tokens": 2}
+}`, w.String())
+}
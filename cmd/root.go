@@ -64,6 +64,7 @@ func init() {
 	rootCmd.AddCommand(versionCmd(), infoCmd())
 	initK9sFlags()
 	initK8sFlags()
+	rootCmd.AddCommand(askCmd())
 }
 
 // Execute root command.
@@ -77,13 +78,9 @@ func run(*cobra.Command, []string) error {
 	if err := config.InitLocs(); err != nil {
 		return err
 	}
-	logFile, err := os.OpenFile(
-		*k9sFlags.LogFile,
-		os.O_CREATE|os.O_APPEND|os.O_WRONLY,
-		data.DefaultFileMod,
-	)
+	logFile, err := initLogs()
 	if err != nil {
-		return fmt.Errorf("log file %q init failed: %w", *k9sFlags.LogFile, err)
+		return err
 	}
 	defer func() {
 		if logFile != nil {
@@ -100,11 +97,6 @@ func run(*cobra.Command, []string) error {
 		}
 	}()
 
-	slog.SetDefault(slog.New(tint.NewHandler(logFile, &tint.Options{
-		Level:      parseLevel(*k9sFlags.LogLevel),
-		TimeFormat: time.RFC3339,
-	})))
-
 	cfg, err := loadConfiguration()
 	if err != nil {
 		slog.Warn("Fail to load global/context configuration", slogs.Error, err)
@@ -127,6 +119,24 @@ func run(*cobra.Command, []string) error {
 	return nil
 }
 
+// initLogs directs logs to the log file.
+func initLogs() (*os.File, error) {
+	logFile, err := os.OpenFile(
+		*k9sFlags.LogFile,
+		os.O_CREATE|os.O_APPEND|os.O_WRONLY,
+		data.DefaultFileMod,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("log file %q init failed: %w", *k9sFlags.LogFile, err)
+	}
+	slog.SetDefault(slog.New(tint.NewHandler(logFile, &tint.Options{
+		Level:      parseLevel(*k9sFlags.LogLevel),
+		TimeFormat: time.RFC3339,
+	})))
+
+	return logFile, nil
+}
+
 func loadConfiguration() (*config.Config, error) {
 	slog.Info("🐶 K9s starting up...")
 
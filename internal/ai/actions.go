@@ -0,0 +1,297 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of K9s
+
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pmezard/go-difflib/difflib"
+	"github.com/quentincherifi/c9s/internal/client"
+	"github.com/quentincherifi/c9s/internal/dao"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	// ActionPatch applies a JSON patch to a resource.
+	ActionPatch = "patch"
+	// ActionScale scales a workload.
+	ActionScale = "scale"
+	// ActionRestart performs a rollout restart.
+	ActionRestart = "restart"
+
+	// ProposeActionTool tracks the remediation proposal tool name.
+	ProposeActionTool = "propose_action"
+	// ProposalRecorded heads a successful proposal tool result, followed by the diff.
+	ProposalRecorded = "Proposal recorded. The user will review the change below and decide whether to apply it:"
+
+	planTimeout = 10 * time.Second
+)
+
+const actionSchema = `{
+  "type": "object",
+  "properties": {
+    "type": {"type": "string", "enum": ["patch", "scale", "restart"]},
+    "kind": {"type": "string", "description": "Resource name, short name or alias, e.g. deploy, sts, apps/v1/deployments"},
+    "namespace": {"type": "string", "description": "Namespace. Omit for cluster scoped resources"},
+    "name": {"type": "string", "description": "Resource name"},
+    "patch": {"type": "array", "description": "RFC 6902 JSON patch operations. Required for patch actions", "items": {"type": "object"}},
+    "replicas": {"type": "integer", "description": "Desired replicas. Required for scale actions"},
+    "reason": {"type": "string", "description": "Short explanation of why this fixes the issue"}
+  },
+  "required": ["type", "kind", "name", "reason"]
+}`
+
+// Action represents a remediation proposed by the model.
+type Action struct {
+	Type      string          `json:"type"`
+	Kind      string          `json:"kind"`
+	Namespace string          `json:"namespace,omitempty"`
+	Name      string          `json:"name"`
+	Patch     json.RawMessage `json:"patch,omitempty"`
+	Replicas  *int32          `json:"replicas,omitempty"`
+	Reason    string          `json:"reason"`
+
+	// GVR tracks the resolved resource type.
+	GVR *client.GVR `json:"-"`
+
+	// Diff tracks the expected change.
+	Diff string `json:"-"`
+
+	// ResourceVersion tracks the resource version a patch was planned against.
+	ResourceVersion string `json:"-"`
+}
+
+// ActionFunc is notified of each validated action proposal.
+type ActionFunc func(*Action)
+
+// Path returns the target resource path.
+func (a *Action) Path() string {
+	return fqnFor(a.GVR, a.Namespace, a.Name)
+}
+
+// String returns a one line summary.
+func (a *Action) String() string {
+	target := a.Kind + " " + client.FQN(a.Namespace, a.Name)
+	switch a.Type {
+	case ActionScale:
+		if a.Replicas != nil {
+			return fmt.Sprintf("Scale %s to %d", target, *a.Replicas)
+		}
+	case ActionRestart:
+		return "Restart " + target
+	}
+
+	return "Patch " + target
+}
+
+// GuardedPatch returns the patch preceded by a test of the resource version
+// it was planned against, so index based paths never hit another element
+// once the resource changed.
+func (a *Action) GuardedPatch() (json.RawMessage, error) {
+	if a.ResourceVersion == "" {
+		return nil, errors.New("patch was not planned against a resource version")
+	}
+	var ops []json.RawMessage
+	if err := json.Unmarshal(a.Patch, &ops); err != nil {
+		return nil, err
+	}
+	test, err := json.Marshal(map[string]string{
+		"op":    "test",
+		"path":  "/metadata/resourceVersion",
+		"value": a.ResourceVersion,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(append([]json.RawMessage{test}, ops...))
+}
+
+func (a *Action) validate() error {
+	if a.Name == "" {
+		return errors.New("a resource name is required")
+	}
+	switch a.Type {
+	case ActionPatch:
+		var ops []map[string]any
+		if err := json.Unmarshal(a.Patch, &ops); err != nil || len(ops) == 0 {
+			return errors.New("patch actions require a non empty JSON patch array")
+		}
+	case ActionScale:
+		if a.Replicas == nil || *a.Replicas < 0 {
+			return errors.New("scale actions require a non negative replicas count")
+		}
+	case ActionRestart:
+	default:
+		return fmt.Errorf("unsupported action type %q", a.Type)
+	}
+
+	return nil
+}
+
+// RegisterActions adds a tool letting the model propose remediation
+// actions. Proposals are validated and dry-run, never applied.
+func RegisterActions(tb *Toolbox, f dao.Factory, r Resolver, fn ActionFunc) {
+	tb.Register(Tool{
+		Name: ProposeActionTool,
+		Description: "Proposes a fix for the user to review and apply: a JSON patch against a resource, " +
+			"scaling a workload or restarting a workload. Nothing is changed until the user confirms.",
+		InputSchema: json.RawMessage(actionSchema),
+	}, func(ctx context.Context, in json.RawMessage) (string, error) {
+		var a Action
+		if err := json.Unmarshal(in, &a); err != nil {
+			return "", err
+		}
+		if err := PlanAction(ctx, f, r, &a); err != nil {
+			return "", err
+		}
+		fn(&a)
+
+		return ProposalRecorded + "\n" + a.Diff, nil
+	})
+}
+
+// PlanAction validates an action and computes its expected change.
+// Patches are dry-run against the api server.
+func PlanAction(ctx context.Context, f dao.Factory, r Resolver, a *Action) error {
+	if err := a.validate(); err != nil {
+		return err
+	}
+	gvr, err := ResolveKind(r, a.Kind)
+	if err != nil {
+		return err
+	}
+	if gvr == client.SecGVR {
+		return ErrSecretContent
+	}
+	a.GVR = gvr
+	acc, err := dao.AccessorFor(f, gvr)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, planTimeout)
+	defer cancel()
+
+	switch a.Type {
+	case ActionScale:
+		if _, ok := acc.(dao.Scalable); !ok {
+			return fmt.Errorf("%s can not be scaled", gvr)
+		}
+		from := "?"
+		if rg, ok := acc.(dao.ReplicasGetter); ok {
+			n, err := rg.Replicas(ctx, a.Path())
+			if err != nil {
+				return err
+			}
+			from = fmt.Sprintf("%d", n)
+		}
+		a.Diff = fmt.Sprintf("- replicas: %s\n+ replicas: %d\n", from, *a.Replicas)
+	case ActionRestart:
+		if _, ok := acc.(dao.Restartable); !ok {
+			return fmt.Errorf("%s can not be restarted", gvr)
+		}
+		a.Diff = "+ spec.template.metadata.annotations[kubectl.kubernetes.io/restartedAt]\n"
+	case ActionPatch:
+		a.Diff, err = dryRunPatch(ctx, f, acc, a)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func dryRunPatch(ctx context.Context, f dao.Factory, acc dao.Accessor, a *Action) (string, error) {
+	p, ok := acc.(dao.Patchable)
+	if !ok {
+		return "", fmt.Errorf("%s can not be patched", a.GVR)
+	}
+	_, before, err := FetchResource(f, a.GVR, a.Path())
+	if err != nil {
+		return "", err
+	}
+	o, err := p.Patch(ctx, a.Path(), types.JSONPatchType, a.Patch, metav1.PatchOptions{
+		DryRun: []string{metav1.DryRunAll},
+	})
+	if err != nil {
+		return "", err
+	}
+	m, err := meta.Accessor(o)
+	if err != nil {
+		return "", err
+	}
+	a.ResourceVersion = m.GetResourceVersion()
+	after, err := dao.ToYAML(o, false)
+	if err != nil {
+		return "", err
+	}
+
+	return Diff(before, after)
+}
+
+// ApplyAction carries out a previously planned action.
+func ApplyAction(ctx context.Context, f dao.Factory, a *Action) error {
+	if a.GVR == nil {
+		return errors.New("action was not planned")
+	}
+	acc, err := dao.AccessorFor(f, a.GVR)
+	if err != nil {
+		return err
+	}
+
+	switch a.Type {
+	case ActionScale:
+		s, ok := acc.(dao.Scalable)
+		if !ok {
+			return fmt.Errorf("%s can not be scaled", a.GVR)
+		}
+		return s.Scale(ctx, a.Path(), *a.Replicas)
+	case ActionRestart:
+		r, ok := acc.(dao.Restartable)
+		if !ok {
+			return fmt.Errorf("%s can not be restarted", a.GVR)
+		}
+		return r.Restart(ctx, a.Path(), &metav1.PatchOptions{})
+	case ActionPatch:
+		p, ok := acc.(dao.Patchable)
+		if !ok {
+			return fmt.Errorf("%s can not be patched", a.GVR)
+		}
+		patch, err := a.GuardedPatch()
+		if err != nil {
+			return err
+		}
+		_, err = p.Patch(ctx, a.Path(), types.JSONPatchType, patch, metav1.PatchOptions{})
+		return err
+	default:
+		return fmt.Errorf("unsupported action type %q", a.Type)
+	}
+}
+
+// Diff returns a unified diff between two manifests.
+func Diff(before, after string) (string, error) {
+	d, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(before),
+		B:        difflib.SplitLines(after),
+		FromFile: "current",
+		ToFile:   "proposed",
+		Context:  2,
+	})
+	if err != nil {
+		return "", err
+	}
+	if strings.TrimSpace(d) == "" {
+		return "", errors.New("the patch does not change the resource")
+	}
+
+	return d, nil
+}
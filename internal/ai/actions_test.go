@@ -0,0 +1,112 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of K9s
+
+package ai_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/quentincherifi/c9s/internal/ai"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestActionString(t *testing.T) {
+	n := int32(3)
+	uu := map[string]struct {
+		a ai.Action
+		e string
+	}{
+		"scale": {
+			a: ai.Action{Type: ai.ActionScale, Kind: "deploy", Namespace: "ns1", Name: "fred", Replicas: &n},
+			e: "Scale deploy ns1/fred to 3",
+		},
+		"restart": {
+			a: ai.Action{Type: ai.ActionRestart, Kind: "sts", Namespace: "ns1", Name: "fred"},
+			e: "Restart sts ns1/fred",
+		},
+		"patch": {
+			a: ai.Action{Type: ai.ActionPatch, Kind: "nodes", Name: "n1"},
+			e: "Patch nodes n1",
+		},
+	}
+
+	for k := range uu {
+		u := uu[k]
+		t.Run(k, func(t *testing.T) {
+			assert.Equal(t, u.e, u.a.String())
+		})
+	}
+}
+
+func TestActionGuardedPatch(t *testing.T) {
+	a := ai.Action{
+		Type:  ai.ActionPatch,
+		Patch: json.RawMessage(`[{"op":"replace","path":"/spec/template/spec/containers/0/image","value":"nginx:1.27"}]`),
+	}
+	_, err := a.GuardedPatch()
+	require.Error(t, err)
+
+	a.ResourceVersion = "4242"
+	p, err := a.GuardedPatch()
+	require.NoError(t, err)
+	assert.JSONEq(t, `[
+  {"op":"test","path":"/metadata/resourceVersion","value":"4242"},
+  {"op":"replace","path":"/spec/template/spec/containers/0/image","value":"nginx:1.27"}
+]`, string(p))
+}
+
+func TestPlanActionInvalid(t *testing.T) {
+	n := int32(-1)
+	uu := map[string]struct {
+		a   ai.Action
+		err string
+	}{
+		"no-name": {
+			a:   ai.Action{Type: ai.ActionRestart, Kind: "deploy"},
+			err: "a resource name is required",
+		},
+		"bad-type": {
+			a:   ai.Action{Type: "delete", Kind: "deploy", Name: "fred"},
+			err: `unsupported action type "delete"`,
+		},
+		"no-replicas": {
+			a:   ai.Action{Type: ai.ActionScale, Kind: "deploy", Name: "fred"},
+			err: "scale actions require a non negative replicas count",
+		},
+		"negative-replicas": {
+			a:   ai.Action{Type: ai.ActionScale, Kind: "deploy", Name: "fred", Replicas: &n},
+			err: "scale actions require a non negative replicas count",
+		},
+		"empty-patch": {
+			a:   ai.Action{Type: ai.ActionPatch, Kind: "deploy", Name: "fred", Patch: json.RawMessage(`[]`)},
+			err: "patch actions require a non empty JSON patch array",
+		},
+		"bad-patch": {
+			a:   ai.Action{Type: ai.ActionPatch, Kind: "deploy", Name: "fred", Patch: json.RawMessage(`{"spec":{}}`)},
+			err: "patch actions require a non empty JSON patch array",
+		},
+	}
+
+	for k := range uu {
+		u := uu[k]
+		t.Run(k, func(t *testing.T) {
+			err := ai.PlanAction(context.Background(), nil, nil, &u.a)
+			assert.EqualError(t, err, u.err)
+		})
+	}
+}
+
+func TestDiff(t *testing.T) {
+	d, err := ai.Diff("a: 1\nb: 2\n", "a: 1\nb: 3\n")
+	require.NoError(t, err)
+	assert.Contains(t, d, "--- current")
+	assert.Contains(t, d, "+++ proposed")
+	assert.Contains(t, d, "-b: 2")
+	assert.Contains(t, d, "+b: 3")
+
+	_, err = ai.Diff("a: 1\n", "a: 1\n")
+	assert.Error(t, err)
+}
@@ -0,0 +1,77 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of K9s
+
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+)
+
+const (
+	anthropicBaseURL    = "https://api.anthropic.com/v1"
+	anthropicAPIVersion = "2023-06-01"
+)
+
+var _ Provider = (*anthropic)(nil)
+
+// anthropic talks to the Anthropic messages API.
+type anthropic struct {
+	transport
+
+	apiKey string
+}
+
+// Name returns the provider name.
+func (*anthropic) Name() string {
+	return "anthropic"
+}
+
+// Send issues a request and returns the complete response.
+func (a *anthropic) Send(ctx context.Context, req Request) (*Response, error) {
+	req.Stream = false
+	resp, err := a.post(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	var res Response
+	if err := decode(resp, &res); err != nil {
+		return nil, err
+	}
+
+	return &res, nil
+}
+
+// Stream issues a streaming request.
+func (a *anthropic) Stream(ctx context.Context, req Request, fn StreamFunc) (*Response, error) {
+	req.Stream = true
+	resp, err := a.post(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	return readStream(resp.Body, fn)
+}
+
+func (a *anthropic) post(ctx context.Context, req Request) (*http.Response, error) {
+	hh := http.Header{}
+	hh.Set("x-api-key", a.apiKey)
+	hh.Set("anthropic-version", anthropicAPIVersion)
+	if req.Stream {
+		hh.Set("Accept", "text/event-stream")
+	}
+
+	return a.transport.post(ctx, "/messages", req, hh, anthropicError)
+}
+
+func anthropicError(bb []byte) (typ, msg string, ok bool) {
+	var errResp ErrorResponse
+	if err := json.Unmarshal(bb, &errResp); err != nil || errResp.Error.Type == "" {
+		return "", "", false
+	}
+
+	return errResp.Error.Type, errResp.Error.Message, true
+}
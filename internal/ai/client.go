@@ -4,58 +4,261 @@
 package ai
 
 import (
-	"bytes"
+	"context"
 	"encoding/json"
-	"fmt"
-	"io"
-	"net/http"
+	"log/slog"
+	"slices"
+	"strings"
 	"time"
-)
 
-const (
-	claudeAPIURL     = "https://api.anthropic.com/v1/messages"
-	claudeAPIVersion = "2023-06-01"
-	defaultTimeout   = 60 * time.Second
+	"github.com/quentincherifi/c9s/internal/config"
+	"github.com/quentincherifi/c9s/internal/slogs"
 )
 
-// Client is a Claude API client.
+const defaultTimeout = 60 * time.Second
+
+// Client is an AI model client. Built-in redaction rules apply to every
+// request.
 type Client struct {
-	apiKey     string
-	model      string
-	maxTokens  int
-	httpClient *http.Client
+	provider    Provider
+	model       string
+	maxTokens   int
+	temperature *float64
+	redactor    *Redactor
+	ledger      *Ledger
+	retry       RetryPolicy
+	onRetry     RetryFunc
 }
 
 // NewClient creates a new Claude API client.
 func NewClient(apiKey, model string, maxTokens int) *Client {
-	return &Client{
+	hc, _ := newHTTPClient(nil)
+
+	return NewProviderClient(&anthropic{
+		transport: transport{baseURL: anthropicBaseURL, httpClient: hc},
 		apiKey:    apiKey,
+	}, model, maxTokens)
+}
+
+// NewProviderClient creates a new client for the given provider.
+func NewProviderClient(p Provider, model string, maxTokens int) *Client {
+	r, _ := NewRedactor(nil)
+
+	return &Client{
+		provider:  p,
 		model:     model,
 		maxTokens: maxTokens,
-		httpClient: &http.Client{
-			Timeout: defaultTimeout,
-		},
+		redactor:  r,
+		retry:     NewRetryPolicy(config.DefaultAIMaxRetries),
+	}
+}
+
+// NewConfigClient creates a new client as described by the AI configuration.
+func NewConfigClient(cfg *config.AI, apiKey string) (*Client, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
 	}
+	p, err := NewProvider(cfg, apiKey)
+	if err != nil {
+		return nil, err
+	}
+	r, err := NewRedactor(cfg.Redact)
+	if err != nil {
+		return nil, err
+	}
+	c := NewProviderClient(p, cfg.GetModel(), cfg.GetMaxTokens())
+	c.SetRedactor(r)
+	c.SetTemperature(cfg.Temperature)
+	c.SetRetryPolicy(NewRetryPolicy(cfg.GetMaxRetries()))
+	if config.AppClaudeUsageFile != "" {
+		c.SetLedger(NewLedger(config.AppClaudeUsageFile, cfg.Budget))
+	}
+
+	return c, nil
 }
 
-// Message represents a chat message.
+// Provider returns the client provider.
+func (c *Client) Provider() Provider {
+	return c.provider
+}
+
+// SetRedactor sets the redactor applied to every request.
+func (c *Client) SetRedactor(r *Redactor) {
+	if r != nil {
+		c.redactor = r
+	}
+}
+
+// Redact masks sensitive values using the client redactor.
+func (c *Client) Redact(s string) string {
+	s, _ = c.redactor.Redact(s)
+
+	return s
+}
+
+// SetTemperature sets the sampling temperature. Providers use their own
+// default when not set.
+func (c *Client) SetTemperature(t *float64) {
+	c.temperature = t
+}
+
+// SetLedger sets the ledger recording usage and enforcing budgets.
+func (c *Client) SetLedger(l *Ledger) {
+	c.ledger = l
+}
+
+// Ledger returns the client ledger if any.
+func (c *Client) Ledger() *Ledger {
+	return c.ledger
+}
+
+// Preview returns the request, as sent to the API, for a conversation.
+// It also returns the number of values redacted.
+func (c *Client) Preview(system string, messages []Message, tb *Toolbox) (string, int, error) {
+	req, n := c.redactor.RedactRequest(c.request(system, messages, tb))
+	req.Stream = true
+	bb, err := json.MarshalIndent(req, "", "  ")
+	if err != nil {
+		return "", 0, err
+	}
+
+	return string(bb), n, nil
+}
+
+func (c *Client) request(system string, messages []Message, tb *Toolbox) Request {
+	return Request{
+		Model:       c.model,
+		MaxTokens:   c.maxTokens,
+		Temperature: c.temperature,
+		System:      system,
+		Messages:    WithoutNotices(messages),
+		Tools:       tb.Tools(),
+	}
+}
+
+// WithoutNotices returns the messages, notices excluded.
+func WithoutNotices(mm []Message) []Message {
+	if !slices.ContainsFunc(mm, isNotice) {
+		return mm
+	}
+
+	return slices.DeleteFunc(slices.Clone(mm), isNotice)
+}
+
+func isNotice(m Message) bool {
+	return m.Notice
+}
+
+// Message represents a chat message. When Blocks is set it takes precedence
+// over Content, which is how tool use exchanges are carried.
 type Message struct {
-	Role    string `json:"role"`
-	Content string `json:"content"`
+	Role    string
+	Content string
+	Blocks  []ContentBlock
+
+	// Usage tracks the tokens consumed producing an answer. It is never
+	// sent to the API.
+	Usage *Usage
+
+	// Pinned messages are kept verbatim when the conversation is compacted.
+	Pinned bool
+
+	// Notice messages are generated locally, i.e. errors. They are only
+	// displayed and never sent to the API, persisted or exported.
+	Notice bool
+}
+
+type wireMessage struct {
+	Role    string          `json:"role"`
+	Content json.RawMessage `json:"content"`
+}
+
+// MarshalJSON encodes a message using either a plain text or block content.
+func (m Message) MarshalJSON() ([]byte, error) {
+	var (
+		content []byte
+		err     error
+	)
+	if len(m.Blocks) > 0 {
+		content, err = json.Marshal(m.Blocks)
+	} else {
+		content, err = json.Marshal(m.Content)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(wireMessage{Role: m.Role, Content: content})
+}
+
+// UnmarshalJSON decodes a message with either a plain text or block content.
+func (m *Message) UnmarshalJSON(bb []byte) error {
+	var w wireMessage
+	if err := json.Unmarshal(bb, &w); err != nil {
+		return err
+	}
+	m.Role, m.Content, m.Blocks = w.Role, "", nil
+	if len(w.Content) == 0 {
+		return nil
+	}
+	if w.Content[0] == '[' {
+		return json.Unmarshal(w.Content, &m.Blocks)
+	}
+
+	return json.Unmarshal(w.Content, &m.Content)
+}
+
+// Text returns the message text, joining text blocks if any.
+func (m *Message) Text() string {
+	if len(m.Blocks) == 0 {
+		return m.Content
+	}
+	tt := make([]string, 0, len(m.Blocks))
+	for _, b := range m.Blocks {
+		if b.Type == BlockText && b.Text != "" {
+			tt = append(tt, b.Text)
+		}
+	}
+
+	return strings.Join(tt, "\n")
 }
 
 // Request represents a Claude API request.
 type Request struct {
-	Model     string    `json:"model"`
-	MaxTokens int       `json:"max_tokens"`
-	System    string    `json:"system,omitempty"`
-	Messages  []Message `json:"messages"`
+	Model       string    `json:"model"`
+	MaxTokens   int       `json:"max_tokens"`
+	Temperature *float64  `json:"temperature,omitempty"`
+	System      string    `json:"system,omitempty"`
+	Messages    []Message `json:"messages"`
+	Tools       []Tool    `json:"tools,omitempty"`
+	Stream      bool      `json:"stream,omitempty"`
 }
 
-// ContentBlock represents a content block in the response.
+const (
+	// BlockText represents a text content block.
+	BlockText = "text"
+	// BlockToolUse represents a tool invocation requested by the model.
+	BlockToolUse = "tool_use"
+	// BlockToolResult represents the outcome of a tool invocation.
+	BlockToolResult = "tool_result"
+)
+
+// ContentBlock represents a content block in a message.
 type ContentBlock struct {
-	Type string `json:"type"`
-	Text string `json:"text"`
+	Type      string          `json:"type"`
+	Text      string          `json:"text,omitempty"`
+	ID        string          `json:"id,omitempty"`
+	Name      string          `json:"name,omitempty"`
+	Input     json.RawMessage `json:"input,omitempty"`
+	ToolUseID string          `json:"tool_use_id,omitempty"`
+	Content   string          `json:"content,omitempty"`
+	IsError   bool            `json:"is_error,omitempty"`
+}
+
+// Usage tracks token consumption for a request.
+type Usage struct {
+	InputTokens  int `json:"input_tokens"`
+	OutputTokens int `json:"output_tokens"`
 }
 
 // Response represents a Claude API response.
@@ -67,10 +270,7 @@ type Response struct {
 	Model        string         `json:"model"`
 	StopReason   string         `json:"stop_reason"`
 	StopSequence *string        `json:"stop_sequence"`
-	Usage        struct {
-		InputTokens  int `json:"input_tokens"`
-		OutputTokens int `json:"output_tokens"`
-	} `json:"usage"`
+	Usage        Usage          `json:"usage"`
 }
 
 // ErrorResponse represents a Claude API error.
@@ -82,62 +282,59 @@ type ErrorResponse struct {
 	} `json:"error"`
 }
 
-// Send sends a message to Claude and returns the response.
-func (c *Client) Send(system string, messages []Message) (*Response, error) {
-	req := Request{
-		Model:     c.model,
-		MaxTokens: c.maxTokens,
-		System:    system,
-		Messages:  messages,
-	}
-
-	body, err := json.Marshal(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to marshal request: %w", err)
+// Send sends a message to the model and returns the response. Cancelling
+// ctx aborts the request along with any pending retry.
+func (c *Client) Send(ctx context.Context, system string, messages []Message) (*Response, error) {
+	if err := c.checkBudget(); err != nil {
+		return nil, err
 	}
+	req, _ := c.redactor.RedactRequest(c.request(system, messages, nil))
 
-	httpReq, err := http.NewRequest(http.MethodPost, claudeAPIURL, bytes.NewReader(body))
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
+	return c.withRetry(ctx, func() (*Response, error) {
+		ctx, cancel := context.WithTimeout(ctx, defaultTimeout)
+		defer cancel()
 
-	httpReq.Header.Set("Content-Type", "application/json")
-	httpReq.Header.Set("x-api-key", c.apiKey)
-	httpReq.Header.Set("anthropic-version", claudeAPIVersion)
+		return c.provider.Send(ctx, req)
+	})
+}
 
-	resp, err := c.httpClient.Do(httpReq)
-	if err != nil {
-		return nil, fmt.Errorf("failed to send request: %w", err)
+func (c *Client) checkBudget() error {
+	if c.ledger == nil {
+		return nil
 	}
-	defer resp.Body.Close()
+	_, err := c.ledger.Check(time.Now())
 
-	respBody, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read response: %w", err)
-	}
+	return err
+}
 
-	if resp.StatusCode != http.StatusOK {
-		var errResp ErrorResponse
-		if err := json.Unmarshal(respBody, &errResp); err != nil {
-			return nil, fmt.Errorf("API error (status %d): %s", resp.StatusCode, string(respBody))
-		}
-		return nil, fmt.Errorf("API error: %s - %s", errResp.Error.Type, errResp.Error.Message)
+// record adds a response usage to the ledger.
+func (c *Client) record(resp *Response) {
+	if c.ledger == nil || resp == nil {
+		return
 	}
-
-	var claudeResp Response
-	if err := json.Unmarshal(respBody, &claudeResp); err != nil {
-		return nil, fmt.Errorf("failed to parse response: %w", err)
+	if err := c.ledger.Record(time.Now(), resp.Usage); err != nil {
+		slog.Warn("Unable to record AI usage", slogs.Error, err)
 	}
-
-	return &claudeResp, nil
 }
 
 // GetText extracts the text content from a response.
 func (r *Response) GetText() string {
 	for _, block := range r.Content {
-		if block.Type == "text" {
+		if block.Type == BlockText {
 			return block.Text
 		}
 	}
 	return ""
 }
+
+// ToolUses returns the tool invocations requested in a response.
+func (r *Response) ToolUses() []ContentBlock {
+	var bb []ContentBlock
+	for _, block := range r.Content {
+		if block.Type == BlockToolUse {
+			bb = append(bb, block)
+		}
+	}
+
+	return bb
+}
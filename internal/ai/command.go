@@ -0,0 +1,79 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of K9s
+
+package ai
+
+import (
+	"context"
+	"errors"
+	"strings"
+)
+
+// maxCommandAliases caps the size of the known resource aliases shared.
+const maxCommandAliases = 4 * 1024
+
+const commandSystemPrompt = `You translate requests into k9s command lines.
+A k9s command line has the following syntax, all parts but the resource being optional:
+
+<resource> [namespace] [label selector] [/filter] [-f fuzzy-filter] [@context]
+
+- resource: a resource name or alias, i.e. pods, po, deploy, svc, ing, cm, events.
+- namespace: a namespace name or "all" for all namespaces.
+- label selector: a Kubernetes label selector, i.e. app=fred,tier!=web. Quote
+  set based selectors, i.e. 'tier in (api,web)'.
+- /filter: keeps rows matching a regular expression, i.e. /crashloop or
+  /!Running to exclude rows matching Running.
+- -f fuzzy-filter: keeps rows fuzzy matching a term.
+- @context: switches to another kubeconfig context.
+
+Examples:
+- "crashing pods in payments with label tier=api": pods payments tier=api /crashloop
+- "deployments in all namespaces": deploy all
+- "services named like gateway in kube-system": svc kube-system /gateway
+
+Current namespace: {{NS}}
+Known resources and aliases: {{ALIASES}}
+
+Reply with the command line only, on a single line, without a leading colon,
+quotes around the whole line or any explanation.`
+
+// ErrNoCommand indicates the model did not come up with a command.
+var ErrNoCommand = errors.New("no command was suggested")
+
+// TranslateCommand asks the model for the k9s command line matching a
+// request. The command is not validated.
+func (c *Client) TranslateCommand(ctx context.Context, k *K8sContext, request string, aliases []string) (string, error) {
+	system := strings.NewReplacer(
+		"{{NS}}", k.Namespace,
+		"{{ALIASES}}", TrimText(strings.Join(aliases, ", "), maxCommandAliases),
+	).Replace(commandSystemPrompt)
+
+	resp, err := c.Send(ctx, system, []Message{{Role: "user", Content: request}})
+	if err != nil {
+		return "", err
+	}
+	line := ParseCommandLine(resp.GetText())
+	if line == "" {
+		return "", ErrNoCommand
+	}
+
+	return line, nil
+}
+
+// ParseCommandLine extracts a command line from an answer, dropping code
+// fences, inline code markers and a leading colon.
+func ParseCommandLine(s string) string {
+	for _, l := range strings.Split(s, "\n") {
+		l = strings.TrimSpace(l)
+		if l == "" || strings.HasPrefix(l, "```") || strings.HasPrefix(l, "~~~") {
+			continue
+		}
+		l = strings.TrimSpace(strings.Trim(l, "`"))
+		l = strings.TrimSpace(strings.TrimPrefix(l, ":"))
+		if l != "" {
+			return l
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,44 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of K9s
+
+package ai_test
+
+import (
+	"testing"
+
+	"github.com/quentincherifi/c9s/internal/ai"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseCommandLine(t *testing.T) {
+	uu := map[string]struct {
+		s, e string
+	}{
+		"plain": {
+			s: "pods payments tier=api",
+			e: "pods payments tier=api",
+		},
+		"colon": {
+			s: ":deploy all",
+			e: "deploy all",
+		},
+		"inline-code": {
+			s: "`:svc kube-system /gateway`",
+			e: "svc kube-system /gateway",
+		},
+		"fenced": {
+			s: "```\npods payments 'tier in (api,web)'\n```",
+			e: "pods payments 'tier in (api,web)'",
+		},
+		"empty": {
+			s: "\n```\n```",
+		},
+	}
+
+	for k := range uu {
+		u := uu[k]
+		t.Run(k, func(t *testing.T) {
+			assert.Equal(t, u.e, ai.ParseCommandLine(u.s))
+		})
+	}
+}
@@ -0,0 +1,172 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of K9s
+
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	// charsPerToken roughly estimates English and YAML text tokenization.
+	charsPerToken = 4
+
+	// maxCompactMessage caps the size of a message in the transcript summarized.
+	maxCompactMessage = 8 * 1024
+
+	compactSystemPrompt = `You summarize a Kubernetes troubleshooting conversation between a user and
+an assistant integrated into k9s, so it may go on without the full transcript.
+Keep the resources, namespaces, errors, findings, commands and their outcome,
+fixes tried, decisions made and open questions. Drop pleasantries and
+repetitions. Be concise and factual. Reply with the summary only.`
+
+	memoryAck = "Understood. I will carry on from this summary."
+)
+
+// ErrNothingToCompact indicates a conversation is too short to be compacted.
+var ErrNothingToCompact = errors.New("nothing to compact")
+
+// Compaction tracks the leading messages of a conversation summarized into a
+// compact memory.
+type Compaction struct {
+	// Upto tracks the number of leading messages summarized.
+	Upto    int       `json:"upto"`
+	Summary string    `json:"summary"`
+	Count   int       `json:"count"`
+	Before  int       `json:"before"`
+	After   int       `json:"after"`
+	At      time.Time `json:"at"`
+}
+
+// EstimateTokens roughly estimates the tokens a text amounts to.
+func EstimateTokens(s string) int {
+	return (len(s) + charsPerToken - 1) / charsPerToken
+}
+
+// EstimateRequest roughly estimates the tokens a request amounts to.
+func EstimateRequest(system string, mm []Message) int {
+	n := EstimateTokens(system)
+	for i := range mm {
+		if len(mm[i].Blocks) == 0 {
+			n += EstimateTokens(mm[i].Content)
+			continue
+		}
+		bb, _ := json.Marshal(mm[i].Blocks)
+		n += EstimateTokens(string(bb))
+	}
+
+	return n
+}
+
+// Apply returns the messages to send, the summarized ones replaced by the
+// compacted memory.
+func (c *Compaction) Apply(mm []Message) []Message {
+	if c == nil || c.Upto <= 0 || c.Upto > len(mm) {
+		return mm
+	}
+	out := make([]Message, 0, len(mm)-c.Upto+2)
+	out = append(out, c.memory(mm[:c.Upto])...)
+
+	return append(out, mm[c.Upto:]...)
+}
+
+// memory returns a summary exchange, pinned messages quoted verbatim.
+func (c *Compaction) memory(mm []Message) []Message {
+	var sb strings.Builder
+	sb.WriteString("Summary of our earlier conversation:\n")
+	sb.WriteString(c.Summary)
+	for i := range mm {
+		if !mm[i].Pinned {
+			continue
+		}
+		fmt.Fprintf(&sb, "\n\nPinned %s message, verbatim:\n%s", mm[i].Role, mm[i].Text())
+	}
+
+	return []Message{
+		{Role: "user", Content: sb.String()},
+		{Role: "assistant", Content: memoryAck},
+	}
+}
+
+// Compact summarizes the messages preceding the last keep ones, building on
+// the previous compaction if any. Conversations are cut before a question
+// so the remaining messages remain valid.
+func (c *Client) Compact(ctx context.Context, prev *Compaction, system string, mm []Message, keep int) (*Compaction, error) {
+	var from int
+	if prev != nil {
+		from = prev.Upto
+	}
+	upto := compactCut(mm, keep)
+	if upto <= from {
+		return nil, ErrNothingToCompact
+	}
+
+	var sb strings.Builder
+	if prev != nil {
+		sb.WriteString("Summary of the conversation so far:\n")
+		sb.WriteString(prev.Summary)
+		sb.WriteString("\n\nFollowed by:\n")
+	}
+	for i := range mm[from:upto] {
+		transcribe(&sb, &mm[from+i])
+	}
+	resp, err := c.Send(ctx, compactSystemPrompt, []Message{{Role: "user", Content: sb.String()}})
+	if err != nil {
+		return nil, err
+	}
+	summary := strings.TrimSpace(resp.GetText())
+	if summary == "" {
+		return nil, errors.New("compaction returned an empty summary")
+	}
+
+	cc := Compaction{
+		Upto:    upto,
+		Summary: summary,
+		Before:  EstimateRequest(system, prev.Apply(mm)),
+		At:      time.Now(),
+	}
+	if prev != nil {
+		cc.Count = prev.Count
+	}
+	cc.Count++
+	cc.After = EstimateRequest(system, cc.Apply(mm))
+
+	return &cc, nil
+}
+
+// compactCut returns the index of the last question starting the keep most
+// recent messages, or 0 if there is none.
+func compactCut(mm []Message, keep int) int {
+	for i := len(mm) - max(keep, 1); i > 0; i-- {
+		if mm[i].Role == "user" && len(mm[i].Blocks) == 0 {
+			return i
+		}
+	}
+
+	return 0
+}
+
+func transcribe(sb *strings.Builder, m *Message) {
+	if m.Notice {
+		return
+	}
+	if len(m.Blocks) == 0 {
+		fmt.Fprintf(sb, "%s: %s\n\n", m.Role, TrimText(m.Content, maxCompactMessage))
+		return
+	}
+	for _, b := range m.Blocks {
+		switch b.Type {
+		case BlockText:
+			fmt.Fprintf(sb, "%s: %s\n\n", m.Role, TrimText(b.Text, maxCompactMessage))
+		case BlockToolUse:
+			fmt.Fprintf(sb, "%s called %s with %s\n\n", m.Role, b.Name, b.Input)
+		case BlockToolResult:
+			fmt.Fprintf(sb, "tool result: %s\n\n", TrimText(b.Content, maxCompactMessage))
+		}
+	}
+}
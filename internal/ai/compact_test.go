@@ -0,0 +1,90 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of K9s
+
+package ai_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/quentincherifi/c9s/internal/ai"
+	"github.com/quentincherifi/c9s/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEstimateTokens(t *testing.T) {
+	assert.Equal(t, 0, ai.EstimateTokens(""))
+	assert.Equal(t, 1, ai.EstimateTokens("pod"))
+	assert.Equal(t, 3, ai.EstimateTokens("fred-1234"))
+	assert.Equal(t, 3, ai.EstimateRequest("system", []ai.Message{{Role: "user", Content: "pod"}}))
+}
+
+func TestCompactionApply(t *testing.T) {
+	mm := conversation()
+
+	var c *ai.Compaction
+	assert.Equal(t, mm, c.Apply(mm))
+
+	c = &ai.Compaction{Upto: 4, Summary: "fred crashes on a missing secret."}
+	out := c.Apply(mm)
+	require.Len(t, out, 4)
+	assert.Equal(t, "user", out[0].Role)
+	assert.Contains(t, out[0].Content, "fred crashes on a missing secret.")
+	assert.Contains(t, out[0].Content, "Pinned user message, verbatim:\nWhy is pod fred failing?")
+	assert.NotContains(t, out[0].Content, "Show me the logs")
+	assert.Equal(t, "assistant", out[1].Role)
+	assert.Equal(t, mm[4:], out[2:])
+}
+
+func TestClientCompact(t *testing.T) {
+	var transcript string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req ai.Request
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		transcript = req.Messages[0].Content
+		_, _ = w.Write([]byte(`{"content":[{"type":"text","text":"fred lacks its db secret."}],"stop_reason":"end_turn"}`))
+	}))
+	defer srv.Close()
+
+	c, err := ai.NewConfigClient(&config.AI{BaseURL: srv.URL}, "key")
+	require.NoError(t, err)
+	mm := conversation()
+
+	cc, err := c.Compact(context.Background(), nil, "system", mm, 2)
+	require.NoError(t, err)
+	assert.Equal(t, 4, cc.Upto)
+	assert.Equal(t, 1, cc.Count)
+	assert.Equal(t, "fred lacks its db secret.", cc.Summary)
+	assert.Contains(t, transcript, "user: Show me the logs")
+	assert.NotContains(t, transcript, "How do I create it?")
+
+	_, err = c.Compact(context.Background(), cc, "system", mm, 2)
+	require.ErrorIs(t, err, ai.ErrNothingToCompact)
+
+	mm = append(mm,
+		ai.Message{Role: "user", Content: "Done. What now?"},
+		ai.Message{Role: "assistant", Content: "Restart the deployment."},
+	)
+	cc, err = c.Compact(context.Background(), cc, "system", mm, 2)
+	require.NoError(t, err)
+	assert.Equal(t, 6, cc.Upto)
+	assert.Equal(t, 2, cc.Count)
+	assert.True(t, strings.HasPrefix(transcript, "Summary of the conversation so far:\nfred lacks its db secret."))
+	assert.Contains(t, transcript, "user: How do I create it?")
+}
+
+func conversation() []ai.Message {
+	return []ai.Message{
+		{Role: "user", Content: "Why is pod fred failing?", Pinned: true},
+		{Role: "assistant", Content: "It can not mount secret db."},
+		{Role: "user", Content: "Show me the logs"},
+		{Role: "assistant", Content: strings.Repeat("boom ", 100)},
+		{Role: "user", Content: "How do I create it?"},
+		{Role: "assistant", Content: "Use kubectl create secret."},
+	}
+}
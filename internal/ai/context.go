@@ -16,7 +16,16 @@ type K8sContext struct {
 	ResourceType     string
 	SelectedResource string
 	ResourceYAML     string
+	Tree             string
 	Events           string
+	RowsSource       string
+	Rows             string
+	LogSource        string
+	Logs             string
+	Runbooks         []Excerpt
+	Withheld         string
+	Instructions     string
+	ToolsEnabled     bool
 }
 
 const systemPromptTemplate = `You are a Kubernetes assistant integrated into k9s.
@@ -32,15 +41,53 @@ Selected Resource: {{.SelectedResource}}
 Resource YAML:
 {{.ResourceYAML}}
 {{- end}}
+{{- if .Tree}}
+Dependency Tree, one resource per line indented under its owner with its [status] and details:
+{{.Tree}}
+Resources flagged missing are referenced but do not exist.
+{{- end}}
+{{- end}}
+{{- if .Rows}}
+{{.RowsSource}} from the current view:
+{{.Rows}}
 {{- end}}
 {{- if .Events}}
 Recent Events:
 {{.Events}}
 {{- end}}
+{{- if .Logs}}
+Logs from {{.LogSource}}. Each line is prefixed with its line number:
+{{.Logs}}
+{{- end}}
+{{- if .Runbooks}}
+
+Team runbooks excerpts relevant to the question:
+{{- range .Runbooks}}
+--- Runbook: {{.File}}{{if .Heading}} ({{.Heading}}){{end}}
+{{.Text}}
+{{- end}}
+---
+Follow the team conventions described in these runbooks. When an answer relies on
+a runbook, cite it inline as [runbook: <file>] and end the answer with a
+"Sources:" line listing the runbook files used.
+{{- end}}
+
+{{- if .Withheld}}
 
+The data sharing policy of this context withholds {{.Withheld}}.
+Work from what is shared and do not ask the user for withheld information.
+{{- end}}
+
+{{if .Instructions -}}
+{{.Instructions}}
+{{- else -}}
 Help the user understand and troubleshoot their Kubernetes resources.
 Be concise and actionable. Suggest k9s commands when relevant (e.g., ":pods", ":logs", ":describe").
-When providing solutions, explain the root cause first, then the fix.`
+When providing solutions, explain the root cause first, then the fix.
+{{- end}}
+{{- if .ToolsEnabled}}
+Use the provided read-only tools to look up live cluster state instead of guessing.
+{{- end}}`
 
 var systemTmpl = template.Must(template.New("system").Parse(systemPromptTemplate))
 
@@ -0,0 +1,55 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of K9s
+
+package ai_test
+
+import (
+	"testing"
+
+	"github.com/quentincherifi/c9s/internal/ai"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildSystemPromptInstructions(t *testing.T) {
+	uu := map[string]struct {
+		k       ai.K8sContext
+		e, notE []string
+	}{
+		"default": {
+			k: ai.K8sContext{ToolsEnabled: true},
+			e: []string{
+				"\n\nHelp the user understand and troubleshoot",
+				"Use the provided read-only tools",
+				"explain the root cause first, then the fix.",
+			},
+		},
+		"override": {
+			k: ai.K8sContext{Instructions: "Answer in one sentence.", ToolsEnabled: true},
+			e: []string{
+				"Namespace: \n",
+				"\n\nAnswer in one sentence.\nUse the provided read-only tools",
+			},
+			notE: []string{"Help the user", "root cause first"},
+		},
+		"override-no-tools": {
+			k:    ai.K8sContext{Instructions: "Answer in one sentence."},
+			e:    []string{"\n\nAnswer in one sentence."},
+			notE: []string{"read-only tools"},
+		},
+	}
+
+	for k := range uu {
+		u := uu[k]
+		t.Run(k, func(t *testing.T) {
+			p, err := ai.BuildSystemPrompt(&u.k)
+			require.NoError(t, err)
+			for _, s := range u.e {
+				assert.Contains(t, p, s)
+			}
+			for _, s := range u.notE {
+				assert.NotContains(t, p, s)
+			}
+		})
+	}
+}
@@ -0,0 +1,534 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of K9s
+
+package ai
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/quentincherifi/c9s/internal/client"
+	"github.com/quentincherifi/c9s/internal/config"
+	"github.com/quentincherifi/c9s/internal/dao"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+const (
+	maxDiagnosePods      = 3
+	maxOwnerDepth        = 5
+	maxWarnings          = 20
+	previousLogLines     = 50
+	unknownConfidence    = "Unknown"
+	diagnoseSystemPrompt = `You are a Kubernetes troubleshooting assistant integrated into k9s.
+You are given evidence gathered about a resource. Only rely on this evidence and
+say so when it is not conclusive.
+Answer in Markdown using exactly these sections:
+### Root Cause
+### Supporting Evidence
+### Remediation
+End with a single line formatted as "Confidence: High|Medium|Low - <reason>".`
+)
+
+var confidenceRX = regexp.MustCompile(`(?im)^[\W_]*confidence[\W_]*(high|medium|low)\b`)
+
+// ContainerLogs tracks the logs of a container.
+type ContainerLogs struct {
+	Pod       string
+	Container string
+	Logs      string
+}
+
+// Evidence tracks the facts gathered about a resource to diagnose it.
+type Evidence struct {
+	Kind         string
+	Path         string
+	Describe     string
+	Owners       []string
+	Warnings     []string
+	Terminations []Termination
+	PreviousLogs []ContainerLogs
+	Nodes        []string
+	Claims       []string
+	Services     []string
+
+	// Issues tracks evidence that could not be gathered.
+	Issues []string
+}
+
+// GatherEvidence collects the evidence needed to diagnose a pod or a
+// workload: its description, owner chain, warning events along with the
+// state of the pods it manages, their nodes, claims and services. Pieces
+// that cannot be gathered are noted as issues rather than failing.
+func GatherEvidence(ctx context.Context, f dao.Factory, gvr *client.GVR, path string, cfg *config.AI) (*Evidence, error) {
+	if gvr == client.SecGVR {
+		return nil, ErrSecretContent
+	}
+	o, err := f.Get(gvr, path, true, labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	u, ok := o.(*unstructured.Unstructured)
+	if !ok {
+		return nil, fmt.Errorf("unsupported object type: %T", o)
+	}
+
+	e := Evidence{Kind: u.GetKind(), Path: path}
+	if raw, err := dao.Describe(f.Client(), gvr, path); err != nil {
+		e.issue("describe", err)
+	} else {
+		e.Describe = TrimText(raw, cfg.GetMaxYAMLSize())
+	}
+	e.Owners = ownerChain(f, u)
+
+	pp, err := podsFor(f, gvr, u)
+	if err != nil {
+		e.issue("pods", err)
+	}
+	names := []string{u.GetName()}
+	for i := range pp {
+		names = append(names, pp[i].Name)
+		e.Terminations = append(e.Terminations, podTerminations(&pp[i])...)
+		e.previousLogs(ctx, f, &pp[i], cfg)
+	}
+	e.warnings(f, u.GetNamespace(), names)
+	e.nodes(f, pp)
+	e.claims(f, pp)
+	e.services(f, u.GetNamespace(), pp)
+
+	return &e, nil
+}
+
+func (e *Evidence) issue(what string, err error) {
+	e.Issues = append(e.Issues, fmt.Sprintf("%s: %v", what, err))
+}
+
+// ownerChain walks up the controller references of an object.
+func ownerChain(f dao.Factory, u *unstructured.Unstructured) []string {
+	var oo []string
+	for range maxOwnerDepth {
+		refs := u.GetOwnerReferences()
+		if len(refs) == 0 {
+			break
+		}
+		ref := metav1.GetControllerOfNoCopy(u)
+		if ref == nil {
+			ref = &refs[0]
+		}
+		gvr, path, err := dao.MetaAccess.OwnerFor(u.GetNamespace(), ref)
+		if err != nil {
+			oo = append(oo, ref.Kind+" "+ref.Name)
+			break
+		}
+		oo = append(oo, ref.Kind+" "+path)
+		o, err := f.Get(gvr, path, true, labels.Everything())
+		if err != nil {
+			break
+		}
+		if u, _ = o.(*unstructured.Unstructured); u == nil {
+			break
+		}
+	}
+
+	return oo
+}
+
+// podsFor returns the pods backing a resource, the least healthy first.
+func podsFor(f dao.Factory, gvr *client.GVR, u *unstructured.Unstructured) ([]v1.Pod, error) {
+	if gvr == client.PodGVR {
+		var po v1.Pod
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, &po); err != nil {
+			return nil, err
+		}
+		return []v1.Pod{po}, nil
+	}
+
+	sel, err := selectorFor(gvr, u)
+	if err != nil || sel == nil {
+		return nil, err
+	}
+	oo, err := f.List(client.PodGVR, u.GetNamespace(), true, sel)
+	if err != nil {
+		return nil, err
+	}
+	pp := make([]v1.Pod, 0, len(oo))
+	for _, o := range oo {
+		pu, ok := o.(*unstructured.Unstructured)
+		if !ok {
+			continue
+		}
+		var po v1.Pod
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(pu.Object, &po); err == nil {
+			pp = append(pp, po)
+		}
+	}
+	sort.SliceStable(pp, func(i, j int) bool {
+		hi, hj := isPodHealthy(&pp[i]), isPodHealthy(&pp[j])
+		if hi != hj {
+			return hj
+		}
+		return podRestarts(&pp[i]) > podRestarts(&pp[j])
+	})
+
+	return pp[:min(len(pp), maxDiagnosePods)], nil
+}
+
+func selectorFor(gvr *client.GVR, u *unstructured.Unstructured) (labels.Selector, error) {
+	if gvr == client.SvcGVR {
+		m, ok, err := unstructured.NestedStringMap(u.Object, "spec", "selector")
+		if err != nil || !ok || len(m) == 0 {
+			return nil, err
+		}
+		return labels.SelectorFromSet(m), nil
+	}
+
+	m, ok, err := unstructured.NestedMap(u.Object, "spec", "selector")
+	if err != nil || !ok {
+		return nil, err
+	}
+	var ls metav1.LabelSelector
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(m, &ls); err != nil {
+		return nil, err
+	}
+
+	return metav1.LabelSelectorAsSelector(&ls)
+}
+
+func isPodHealthy(po *v1.Pod) bool {
+	if po.Status.Phase == v1.PodSucceeded {
+		return true
+	}
+	if po.Status.Phase != v1.PodRunning {
+		return false
+	}
+	for _, cs := range po.Status.ContainerStatuses {
+		if !cs.Ready {
+			return false
+		}
+	}
+
+	return true
+}
+
+func podRestarts(po *v1.Pod) int32 {
+	var n int32
+	for _, cs := range po.Status.ContainerStatuses {
+		n += cs.RestartCount
+	}
+
+	return n
+}
+
+func containerStatuses(po *v1.Pod) []v1.ContainerStatus {
+	return slices.Concat(po.Status.InitContainerStatuses, po.Status.ContainerStatuses)
+}
+
+// Termination describes a container waiting or terminated. The message is
+// kept apart as it may carry log output.
+type Termination struct {
+	State   string
+	Message string
+}
+
+// String returns the termination state followed by its message.
+func (t Termination) String() string {
+	return strings.TrimSpace(t.State + " " + t.Message)
+}
+
+// podTerminations describes containers currently waiting or terminated along
+// with the last time they terminated.
+func podTerminations(po *v1.Pod) []Termination {
+	var tt []Termination
+	for _, cs := range containerStatuses(po) {
+		id := po.Name + "/" + cs.Name
+		if w := cs.State.Waiting; w != nil && w.Reason != "" {
+			tt = append(tt, Termination{
+				State:   fmt.Sprintf("%s is waiting (%s)", id, w.Reason),
+				Message: strings.TrimSpace(w.Message),
+			})
+		}
+		if t := cs.State.Terminated; t != nil {
+			tt = append(tt, Termination{
+				State:   fmt.Sprintf("%s terminated with exit code %d (%s)", id, t.ExitCode, t.Reason),
+				Message: strings.TrimSpace(t.Message),
+			})
+		}
+		if t := cs.LastTerminationState.Terminated; t != nil {
+			tt = append(tt, Termination{
+				State: fmt.Sprintf("%s last terminated at %s with exit code %d (%s), %d restarts",
+					id, t.FinishedAt.UTC().Format(time.RFC3339), t.ExitCode, t.Reason, cs.RestartCount),
+				Message: strings.TrimSpace(t.Message),
+			})
+		}
+	}
+
+	return tt
+}
+
+// previousLogs collects the logs of containers that terminated before.
+func (e *Evidence) previousLogs(ctx context.Context, f dao.Factory, po *v1.Pod, cfg *config.AI) {
+	for _, cs := range containerStatuses(po) {
+		if cs.LastTerminationState.Terminated == nil {
+			continue
+		}
+		logs, err := tailLogs(ctx, f, &dao.LogOptions{
+			Path:      client.FQN(po.Namespace, po.Name),
+			Container: cs.Name,
+			Lines:     previousLogLines,
+			Previous:  true,
+		})
+		if err != nil {
+			e.issue("previous logs "+po.Name+"/"+cs.Name, err)
+			continue
+		}
+		if logs = strings.TrimSpace(logs); logs != "" {
+			e.PreviousLogs = append(e.PreviousLogs, ContainerLogs{
+				Pod:       po.Name,
+				Container: cs.Name,
+				Logs:      TrimText(logs, cfg.GetMaxLogsSize()),
+			})
+		}
+	}
+}
+
+// warnings collects the warning events of the named objects.
+func (e *Evidence) warnings(f dao.Factory, ns string, names []string) {
+	if client.IsClusterScoped(ns) {
+		ns = client.BlankNamespace
+	}
+	if auth, err := f.Client().CanI(ns, client.EvGVR, "", client.ListAccess); err != nil || !auth {
+		return
+	}
+	oo, err := f.List(client.EvGVR, ns, true, labels.Everything())
+	if err != nil {
+		e.issue("events", err)
+		return
+	}
+	for _, n := range names {
+		for _, l := range FilterEvents(oo, "", n) {
+			if ff := strings.Fields(l); len(ff) > 1 && ff[1] == v1.EventTypeWarning {
+				e.Warnings = append(e.Warnings, l)
+			}
+		}
+	}
+	sort.Sort(sort.Reverse(sort.StringSlice(e.Warnings)))
+	e.Warnings = e.Warnings[:min(len(e.Warnings), maxWarnings)]
+}
+
+// nodes collects the conditions of the nodes hosting the pods.
+func (e *Evidence) nodes(f dao.Factory, pp []v1.Pod) {
+	seen := make(map[string]struct{}, len(pp))
+	for i := range pp {
+		n := pp[i].Spec.NodeName
+		if _, ok := seen[n]; ok || n == "" {
+			continue
+		}
+		seen[n] = struct{}{}
+		var no v1.Node
+		if err := getAs(f, client.NodeGVR, n, &no); err != nil {
+			e.issue("node "+n, err)
+			continue
+		}
+		cc := make([]string, 0, len(no.Status.Conditions))
+		for _, c := range no.Status.Conditions {
+			s := fmt.Sprintf("%s=%s", c.Type, c.Status)
+			if nominal := (c.Type == v1.NodeReady) == (c.Status == v1.ConditionTrue); !nominal {
+				s += fmt.Sprintf(" (%s: %s)", c.Reason, c.Message)
+			}
+			cc = append(cc, s)
+		}
+		e.Nodes = append(e.Nodes, n+": "+strings.Join(cc, ", "))
+	}
+}
+
+// claims collects the state of the volume claims mounted by the pods.
+func (e *Evidence) claims(f dao.Factory, pp []v1.Pod) {
+	seen := make(map[string]struct{})
+	for i := range pp {
+		for _, v := range pp[i].Spec.Volumes {
+			if v.PersistentVolumeClaim == nil {
+				continue
+			}
+			path := client.FQN(pp[i].Namespace, v.PersistentVolumeClaim.ClaimName)
+			if _, ok := seen[path]; ok {
+				continue
+			}
+			seen[path] = struct{}{}
+			var pvc v1.PersistentVolumeClaim
+			if err := getAs(f, client.PvcGVR, path, &pvc); err != nil {
+				e.issue("pvc "+path, err)
+				continue
+			}
+			s := fmt.Sprintf("%s: %s", path, pvc.Status.Phase)
+			if pvc.Spec.VolumeName != "" {
+				s += " to " + pvc.Spec.VolumeName
+			}
+			if q, ok := pvc.Status.Capacity[v1.ResourceStorage]; ok {
+				s += " (" + q.String() + ")"
+			}
+			if sc := pvc.Spec.StorageClassName; sc != nil {
+				s += " storageclass " + *sc
+			}
+			e.Claims = append(e.Claims, s)
+		}
+	}
+}
+
+// services collects the services selecting the pods and their endpoints.
+func (e *Evidence) services(f dao.Factory, ns string, pp []v1.Pod) {
+	if len(pp) == 0 {
+		return
+	}
+	oo, err := f.List(client.SvcGVR, ns, true, labels.Everything())
+	if err != nil {
+		e.issue("services", err)
+		return
+	}
+	for _, o := range oo {
+		u, ok := o.(*unstructured.Unstructured)
+		if !ok {
+			continue
+		}
+		var svc v1.Service
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, &svc); err != nil || len(svc.Spec.Selector) == 0 {
+			continue
+		}
+		sel := labels.SelectorFromSet(svc.Spec.Selector)
+		if !slices.ContainsFunc(pp, func(po v1.Pod) bool { return sel.Matches(labels.Set(po.Labels)) }) {
+			continue
+		}
+		ports := make([]string, 0, len(svc.Spec.Ports))
+		for _, p := range svc.Spec.Ports {
+			ports = append(ports, fmt.Sprintf("%d->%s/%s", p.Port, p.TargetPort.String(), p.Protocol))
+		}
+		s := fmt.Sprintf("%s: %s %s", client.FQN(svc.Namespace, svc.Name), svc.Spec.Type, strings.Join(ports, ","))
+		var ep v1.Endpoints
+		if err := getAs(f, client.EpGVR, client.FQN(svc.Namespace, svc.Name), &ep); err == nil {
+			var ready, notReady int
+			for _, ss := range ep.Subsets {
+				ready, notReady = ready+len(ss.Addresses), notReady+len(ss.NotReadyAddresses)
+			}
+			s += fmt.Sprintf(" endpoints %d ready, %d not ready", ready, notReady)
+		}
+		e.Services = append(e.Services, s)
+	}
+}
+
+func getAs(f dao.Factory, gvr *client.GVR, path string, obj any) error {
+	o, err := f.Get(gvr, path, true, labels.Everything())
+	if err != nil {
+		return err
+	}
+	u, ok := o.(*unstructured.Unstructured)
+	if !ok {
+		return fmt.Errorf("unsupported object type: %T", o)
+	}
+
+	return runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, obj)
+}
+
+// Markdown renders the evidence as Markdown sections.
+func (e *Evidence) Markdown() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "## Evidence\n\nResource: %s %s\n", e.Kind, e.Path)
+	mdList(&sb, "Owner Chain", e.Owners)
+	mdList(&sb, "Warning Events", e.Warnings)
+	tt := make([]string, 0, len(e.Terminations))
+	for _, t := range e.Terminations {
+		tt = append(tt, t.String())
+	}
+	mdList(&sb, "Container Terminations", tt)
+	sb.WriteString("\n### Previous Container Logs\n\n")
+	if len(e.PreviousLogs) == 0 {
+		sb.WriteString("None found.\n")
+	}
+	for _, l := range e.PreviousLogs {
+		fmt.Fprintf(&sb, "#### %s/%s\n\n```text\n%s\n```\n", l.Pod, l.Container, l.Logs)
+	}
+	mdList(&sb, "Node Conditions", e.Nodes)
+	mdList(&sb, "Persistent Volume Claims", e.Claims)
+	mdList(&sb, "Services", e.Services)
+	if e.Describe != "" {
+		fmt.Fprintf(&sb, "\n### Describe\n\n```text\n%s\n```\n", strings.TrimSpace(e.Describe))
+	}
+	if len(e.Issues) > 0 {
+		mdList(&sb, "Gathering Issues", e.Issues)
+	}
+
+	return sb.String()
+}
+
+func mdList(sb *strings.Builder, title string, ll []string) {
+	fmt.Fprintf(sb, "\n### %s\n\n", title)
+	if len(ll) == 0 {
+		sb.WriteString("None found.\n")
+		return
+	}
+	for _, l := range ll {
+		fmt.Fprintf(sb, "- %s\n", l)
+	}
+}
+
+// Diagnosis represents a diagnose report.
+type Diagnosis struct {
+	Context    string
+	Cluster    string
+	Model      string
+	At         time.Time
+	Evidence   *Evidence
+	Analysis   string
+	Confidence string
+}
+
+// Diagnose asks the model for the root cause of the issues shown by the
+// evidence.
+func (c *Client) Diagnose(ctx context.Context, k *K8sContext, e *Evidence) (*Diagnosis, error) {
+	d := Diagnosis{
+		Context:  k.ContextName,
+		Cluster:  k.ClusterName,
+		Model:    c.model,
+		At:       time.Now(),
+		Evidence: e,
+	}
+	resp, err := c.Send(ctx, diagnoseSystemPrompt, []Message{{Role: "user", Content: e.Markdown()}})
+	if err != nil {
+		return nil, err
+	}
+	d.Analysis = strings.TrimSpace(resp.GetText())
+	d.Confidence = ParseConfidence(d.Analysis)
+
+	return &d, nil
+}
+
+// ParseConfidence extracts the confidence level stated by an answer.
+func ParseConfidence(s string) string {
+	mm := confidenceRX.FindStringSubmatch(s)
+	if mm == nil {
+		return unknownConfidence
+	}
+
+	return strings.ToUpper(mm[1][:1]) + strings.ToLower(mm[1][1:])
+}
+
+// Markdown renders the report as a Markdown document.
+func (d *Diagnosis) Markdown() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "# Diagnosis: %s %s\n\n", d.Evidence.Kind, d.Evidence.Path)
+	fmt.Fprintf(&sb, "- **Context:** %s\n", d.Context)
+	fmt.Fprintf(&sb, "- **Cluster:** %s\n", d.Cluster)
+	fmt.Fprintf(&sb, "- **Generated:** %s\n", d.At.UTC().Format(time.RFC3339))
+	fmt.Fprintf(&sb, "- **Model:** %s\n", d.Model)
+	fmt.Fprintf(&sb, "- **Confidence:** %s\n", d.Confidence)
+	fmt.Fprintf(&sb, "\n## Root Cause Analysis\n\n%s\n\n", d.Analysis)
+	sb.WriteString(d.Evidence.Markdown())
+
+	return sb.String()
+}
@@ -0,0 +1,156 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of K9s
+
+package ai
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestParseConfidence(t *testing.T) {
+	uu := map[string]struct {
+		s, e string
+	}{
+		"plain": {
+			s: "### Root Cause\nOOM\nConfidence: High - the exit code says so",
+			e: "High",
+		},
+		"bold": {
+			s: "**Confidence:** medium",
+			e: "Medium",
+		},
+		"dash": {
+			s: "- Confidence - LOW",
+			e: "Low",
+		},
+		"missing": {
+			s: "I am fairly confident",
+			e: unknownConfidence,
+		},
+	}
+
+	for k := range uu {
+		u := uu[k]
+		t.Run(k, func(t *testing.T) {
+			assert.Equal(t, u.e, ParseConfidence(u.s))
+		})
+	}
+}
+
+func TestPodTerminations(t *testing.T) {
+	at := metav1.NewTime(time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC))
+	po := v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "fred"},
+		Status: v1.PodStatus{
+			InitContainerStatuses: []v1.ContainerStatus{
+				{
+					Name:  "init",
+					State: v1.ContainerState{Terminated: &v1.ContainerStateTerminated{Reason: "Completed"}},
+				},
+			},
+			ContainerStatuses: []v1.ContainerStatus{
+				{
+					Name:         "c1",
+					RestartCount: 3,
+					State: v1.ContainerState{
+						Waiting: &v1.ContainerStateWaiting{Reason: "CrashLoopBackOff"},
+					},
+					LastTerminationState: v1.ContainerState{
+						Terminated: &v1.ContainerStateTerminated{ExitCode: 137, Reason: "OOMKilled", Message: "out of memory\n", FinishedAt: at},
+					},
+				},
+				{
+					Name:  "c2",
+					Ready: true,
+				},
+			},
+		},
+	}
+
+	tt := podTerminations(&po)
+	assert.Equal(t, []Termination{
+		{State: "fred/init terminated with exit code 0 (Completed)"},
+		{State: "fred/c1 is waiting (CrashLoopBackOff)"},
+		{State: "fred/c1 last terminated at 2025-01-02T03:04:05Z with exit code 137 (OOMKilled), 3 restarts", Message: "out of memory"},
+	}, tt)
+	assert.Equal(t, "fred/c1 last terminated at 2025-01-02T03:04:05Z with exit code 137 (OOMKilled), 3 restarts out of memory", tt[2].String())
+	assert.False(t, isPodHealthy(&po))
+	assert.Equal(t, int32(3), podRestarts(&po))
+}
+
+func TestDiagnosisMarkdown(t *testing.T) {
+	d := Diagnosis{
+		Context:    "ctx1",
+		Cluster:    "cl1",
+		Model:      "m1",
+		At:         time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC),
+		Analysis:   "### Root Cause\nOOM\nConfidence: High",
+		Confidence: "High",
+		Evidence: &Evidence{
+			Kind:         "Pod",
+			Path:         "default/fred",
+			Owners:       []string{"ReplicaSet default/fred-1", "Deployment default/fred"},
+			PreviousLogs: []ContainerLogs{{Pod: "fred", Container: "c1", Logs: "boom"}},
+			Issues:       []string{"node n1: forbidden"},
+		},
+	}
+
+	assert.Equal(t, `# Diagnosis: Pod default/fred
+
+- **Context:** ctx1
+- **Cluster:** cl1
+- **Generated:** 2025-01-02T03:04:05Z
+- **Model:** m1
+- **Confidence:** High
+
+## Root Cause Analysis
+
+### Root Cause
+OOM
+Confidence: High
+
+## Evidence
+
+Resource: Pod default/fred
+
+### Owner Chain
+
+- ReplicaSet default/fred-1
+- Deployment default/fred
+
+### Warning Events
+
+None found.
+
+### Container Terminations
+
+None found.
+
+### Previous Container Logs
+
+#### fred/c1
+
+`+"```text\nboom\n```"+`
+
+### Node Conditions
+
+None found.
+
+### Persistent Volume Claims
+
+None found.
+
+### Services
+
+None found.
+
+### Gathering Issues
+
+- node n1: forbidden
+`, d.Markdown())
+}
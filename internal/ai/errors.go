@@ -0,0 +1,132 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of K9s
+
+package ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrUnreachable indicates the provider could not be reached.
+var ErrUnreachable = errors.New("unable to reach the AI provider")
+
+// statusOverloaded is the Anthropic status for an overloaded API.
+const statusOverloaded = 529
+
+// APIError represents a request rejected by the provider.
+type APIError struct {
+	// StatusCode tracks the HTTP status if any.
+	StatusCode int
+
+	// Type tracks the provider error type, i.e. rate_limit_error.
+	Type string
+
+	// Message tracks the provider error message.
+	Message string
+
+	// RetryAfter tracks how long the provider asked to wait before retrying.
+	RetryAfter time.Duration
+}
+
+// Error returns the error message.
+func (e *APIError) Error() string {
+	if e.Type != "" {
+		return fmt.Sprintf("API error: %s - %s", e.Type, e.Message)
+	}
+
+	return fmt.Sprintf("API error (status %d): %s", e.StatusCode, e.Message)
+}
+
+// Retryable checks if the request may succeed when tried again.
+func (e *APIError) Retryable() bool {
+	switch e.Type {
+	case "rate_limit_error", "overloaded_error", "api_error":
+		return true
+	}
+	switch e.StatusCode {
+	case http.StatusTooManyRequests, http.StatusInternalServerError, http.StatusBadGateway,
+		http.StatusServiceUnavailable, http.StatusGatewayTimeout, statusOverloaded:
+		return true
+	}
+
+	return false
+}
+
+// IsRetryable checks if a failed request may be tried again.
+func IsRetryable(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	if errors.Is(err, ErrUnreachable) {
+		return true
+	}
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.Retryable()
+	}
+
+	return false
+}
+
+// Describe returns a human readable explanation of a failed request.
+func Describe(err error) string {
+	var apiErr *APIError
+	switch {
+	case err == nil:
+		return ""
+	case errors.Is(err, context.DeadlineExceeded):
+		return "The request timed out. Try again or ask a narrower question."
+	case errors.Is(err, ErrBudgetExceeded):
+		return "Request refused, " + strings.TrimPrefix(err.Error(), ErrBudgetExceeded.Error()+": ") + ". The budget is set in the configuration."
+	case errors.Is(err, ErrUnreachable):
+		return fmt.Sprintf("Unable to reach the AI provider. Check your network or the configured base URL (%s).",
+			strings.TrimPrefix(err.Error(), ErrUnreachable.Error()+": "))
+	case !errors.As(err, &apiErr):
+		return err.Error()
+	}
+
+	switch {
+	case apiErr.StatusCode == http.StatusUnauthorized || apiErr.Type == "authentication_error":
+		return "Authentication failed. Check your API key or set it using `:claude set-key`."
+	case apiErr.StatusCode == http.StatusForbidden || apiErr.Type == "permission_error":
+		return "Permission denied: " + apiErr.Message
+	case apiErr.StatusCode == http.StatusNotFound || apiErr.Type == "not_found_error":
+		return "Not found: " + apiErr.Message + ". Check the configured model."
+	case apiErr.StatusCode == http.StatusTooManyRequests || apiErr.Type == "rate_limit_error":
+		return "Rate limited by the AI provider. Wait a moment and try again."
+	case apiErr.StatusCode == statusOverloaded || apiErr.Type == "overloaded_error":
+		return "The AI provider is overloaded. Wait a moment and try again."
+	case apiErr.StatusCode == http.StatusBadRequest || apiErr.Type == "invalid_request_error":
+		return "Invalid request: " + apiErr.Message
+	case apiErr.StatusCode >= http.StatusInternalServerError || apiErr.Type == "api_error":
+		return "The AI provider failed: " + apiErr.Message
+	}
+
+	return apiErr.Error()
+}
+
+// parseRetryAfter reads a retry-after header expressed either in seconds
+// or as an HTTP date.
+func parseRetryAfter(h string, now time.Time) time.Duration {
+	h = strings.TrimSpace(h)
+	if h == "" {
+		return 0
+	}
+	if s, err := strconv.Atoi(h); err == nil {
+		if s <= 0 {
+			return 0
+		}
+		return time.Duration(s) * time.Second
+	}
+	if t, err := http.ParseTime(h); err == nil && t.After(now) {
+		return t.Sub(now)
+	}
+
+	return 0
+}
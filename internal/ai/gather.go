@@ -0,0 +1,159 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of K9s
+
+package ai
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/quentincherifi/c9s/internal/client"
+	"github.com/quentincherifi/c9s/internal/config"
+	"github.com/quentincherifi/c9s/internal/dao"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// ErrSecretContent indicates secret payloads are never shared with the model.
+var ErrSecretContent = errors.New("secret contents are not shared with the assistant")
+
+// ResourceInfo tracks a resource manifest and its recent events.
+type ResourceInfo struct {
+	Kind   string
+	YAML   string
+	Events []string
+}
+
+// IsK8sResource checks if a gvr represents an actual cluster resource.
+func IsK8sResource(gvr *client.GVR) bool {
+	m, err := dao.MetaAccess.MetaFor(gvr)
+	if err != nil {
+		return false
+	}
+
+	return dao.IsK8sMeta(m)
+}
+
+// FetchResource retrieves a resource manifest, managed fields stripped.
+func FetchResource(f dao.Factory, gvr *client.GVR, path string) (runtime.Object, string, error) {
+	if gvr == client.SecGVR {
+		return nil, "", ErrSecretContent
+	}
+	o, err := f.Get(gvr, path, true, labels.Everything())
+	if err != nil {
+		return nil, "", err
+	}
+	raw, err := dao.ToYAML(o, false)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return o, raw, nil
+}
+
+// GatherResource retrieves a resource manifest along with its recent events.
+// Events are optional and failing to list them is not an error.
+func GatherResource(f dao.Factory, gvr *client.GVR, path string) (*ResourceInfo, error) {
+	o, raw, err := FetchResource(f, gvr, path)
+	if err != nil {
+		return nil, err
+	}
+	info := ResourceInfo{YAML: raw}
+	if o != nil {
+		info.Kind = o.GetObjectKind().GroupVersionKind().Kind
+	}
+
+	ns, n := client.Namespaced(path)
+	if m, err := meta.Accessor(o); err == nil {
+		ns, n = m.GetNamespace(), m.GetName()
+	}
+	if client.IsClusterScoped(ns) {
+		ns = client.BlankNamespace
+	}
+	if auth, err := f.Client().CanI(ns, client.EvGVR, "", client.ListAccess); err != nil || !auth {
+		return &info, nil
+	}
+	if oo, err := f.List(client.EvGVR, ns, true, labels.Everything()); err == nil {
+		info.Events = FilterEvents(oo, info.Kind, n)
+	}
+
+	return &info, nil
+}
+
+// LoadResource gathers a resource manifest along with its recent events
+// into the context, trimmed to the configured budgets.
+func (k *K8sContext) LoadResource(f dao.Factory, gvr *client.GVR, path string, cfg *config.AI) (*ResourceInfo, error) {
+	info, err := GatherResource(f, gvr, path)
+	if err != nil {
+		return nil, err
+	}
+	k.ResourceYAML = TrimText(info.YAML, cfg.GetMaxYAMLSize())
+	k.Events = TrimLines(info.Events, cfg.GetMaxEventsSize())
+
+	return info, nil
+}
+
+// TrimText caps a text to the given byte size. Non positive sizes mean no cap.
+func TrimText(s string, size int) string {
+	if size <= 0 || len(s) <= size {
+		return s
+	}
+
+	return s[:size] + "\n... [truncated]"
+}
+
+// TrimLines keeps as many leading lines as fit in the given byte size.
+// Non positive sizes mean no cap.
+func TrimLines(ll []string, size int) string {
+	if size <= 0 {
+		return strings.Join(ll, "\n")
+	}
+
+	var (
+		sb   strings.Builder
+		kept int
+	)
+	for _, l := range ll {
+		if sb.Len()+len(l)+1 > size {
+			break
+		}
+		if kept > 0 {
+			sb.WriteString("\n")
+		}
+		sb.WriteString(l)
+		kept++
+	}
+	if kept < len(ll) {
+		if kept > 0 {
+			sb.WriteString("\n")
+		}
+		sb.WriteString(fmt.Sprintf("... %d more", len(ll)-kept))
+	}
+
+	return sb.String()
+}
+
+// TableRows renders table rows as pipe separated lines, the header first,
+// capped to the given number of rows. Non positive caps mean no cap.
+func TableRows(header []string, rows [][]string, maxRows int) string {
+	if len(rows) == 0 {
+		return ""
+	}
+	kept := rows
+	if maxRows > 0 && len(rows) > maxRows {
+		kept = rows[:maxRows]
+	}
+
+	ll := make([]string, 0, len(kept)+2)
+	ll = append(ll, strings.Join(header, " | "))
+	for _, r := range kept {
+		ll = append(ll, strings.Join(r, " | "))
+	}
+	if n := len(rows) - len(kept); n > 0 {
+		ll = append(ll, fmt.Sprintf("... %d more rows", n))
+	}
+
+	return strings.Join(ll, "\n")
+}
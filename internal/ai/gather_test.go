@@ -0,0 +1,140 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of K9s
+
+package ai_test
+
+import (
+	"testing"
+
+	"github.com/quentincherifi/c9s/internal/ai"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func TestTrimText(t *testing.T) {
+	uu := map[string]struct {
+		s    string
+		size int
+		e    string
+	}{
+		"no-cap": {
+			s: "hello",
+			e: "hello",
+		},
+		"fits": {
+			s:    "hello",
+			size: 5,
+			e:    "hello",
+		},
+		"trimmed": {
+			s:    "hello world",
+			size: 5,
+			e:    "hello\n... [truncated]",
+		},
+	}
+
+	for k := range uu {
+		u := uu[k]
+		t.Run(k, func(t *testing.T) {
+			assert.Equal(t, u.e, ai.TrimText(u.s, u.size))
+		})
+	}
+}
+
+func TestTrimLines(t *testing.T) {
+	uu := map[string]struct {
+		ll   []string
+		size int
+		e    string
+	}{
+		"empty": {},
+		"no-cap": {
+			ll: []string{"a", "b"},
+			e:  "a\nb",
+		},
+		"fits": {
+			ll:   []string{"aaa", "bbb"},
+			size: 8,
+			e:    "aaa\nbbb",
+		},
+		"trimmed": {
+			ll:   []string{"aaa", "bbb", "ccc"},
+			size: 8,
+			e:    "aaa\nbbb\n... 1 more",
+		},
+		"none-fit": {
+			ll:   []string{"aaaaaaaa"},
+			size: 4,
+			e:    "... 1 more",
+		},
+	}
+
+	for k := range uu {
+		u := uu[k]
+		t.Run(k, func(t *testing.T) {
+			assert.Equal(t, u.e, ai.TrimLines(u.ll, u.size))
+		})
+	}
+}
+
+func TestFilterEvents(t *testing.T) {
+	oo := []runtime.Object{
+		makeEvent("e1", "Pod", "p1", "Warning", "BackOff", "2024-01-01T10:00:00Z"),
+		makeEvent("e2", "Pod", "p2", "Normal", "Pulled", "2024-01-01T11:00:00Z"),
+		makeEvent("e3", "Pod", "p1", "Normal", "Started", "2024-01-01T12:00:00Z"),
+	}
+
+	assert.Equal(t, []string{
+		"2024-01-01T12:00:00Z Normal Started Pod/p1: note e3",
+		"2024-01-01T10:00:00Z Warning BackOff Pod/p1: note e1",
+	}, ai.FilterEvents(oo, "pod", "p1"))
+	assert.Len(t, ai.FilterEvents(oo, "", ""), 3)
+	assert.Empty(t, ai.FilterEvents(oo, "Deployment", "p1"))
+}
+
+func makeEvent(n, kind, name, typ, reason, at string) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "events.k8s.io/v1",
+		"kind":       "Event",
+		"metadata": map[string]any{
+			"name":      n,
+			"namespace": "default",
+		},
+		"eventTime": at[:len(at)-1] + ".000000Z",
+		"regarding": map[string]any{
+			"kind": kind,
+			"name": name,
+		},
+		"type":   typ,
+		"reason": reason,
+		"note":   "note " + n,
+	}}
+}
+
+func TestTableRows(t *testing.T) {
+	hh := []string{"NAME", "STATUS"}
+	uu := map[string]struct {
+		rows [][]string
+		max  int
+		e    string
+	}{
+		"empty": {},
+		"no-cap": {
+			rows: [][]string{{"p1", "Running"}, {"p2", "Error"}},
+			e:    "NAME | STATUS\np1 | Running\np2 | Error",
+		},
+		"capped": {
+			rows: [][]string{{"p1", "Running"}, {"p2", "Error"}, {"p3", "Pending"}},
+			max:  1,
+			e:    "NAME | STATUS\np1 | Running\n... 2 more rows",
+		},
+	}
+
+	for k := range uu {
+		u := uu[k]
+		t.Run(k, func(t *testing.T) {
+			assert.Equal(t, u.e, ai.TableRows(hh, u.rows, u.max))
+		})
+	}
+}
@@ -0,0 +1,240 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of K9s
+
+package ai
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/quentincherifi/c9s/internal/client"
+	"github.com/quentincherifi/c9s/internal/dao"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	kyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"sigs.k8s.io/yaml"
+)
+
+// manifestFieldManager tracks the field manager of applied manifests.
+const manifestFieldManager = "c9s"
+
+const generateSystemPrompt = `You write Kubernetes manifests from a description.
+Current namespace: {{NS}}
+
+Use stable API versions and set resource requests and limits on containers.
+Omit the namespace for resources living in the current namespace.
+Separate multiple resources with a "---" line.
+
+Reply with the manifest only, in a single yaml code block, without any explanation.`
+
+const repairPrompt = `The server-side dry-run apply rejected the manifest:
+%s
+
+Fix the manifest and reply with the whole corrected manifest only, in a single yaml code block.`
+
+// ErrNoManifest indicates the model did not come up with a manifest.
+var ErrNoManifest = errors.New("no manifest was generated")
+
+// ValidateFunc validates a manifest, i.e. by a server-side dry-run.
+type ValidateFunc func(ctx context.Context, manifest string) error
+
+// Generation tracks a generated manifest and how it fared validating it.
+type Generation struct {
+	// Manifest tracks the last manifest generated.
+	Manifest string
+
+	// Repairs tracks how many times the manifest was sent back for repairs.
+	Repairs int
+
+	// Err tracks why the manifest is still invalid if so.
+	Err error
+}
+
+// GenerateManifest asks the model for a manifest matching a description.
+// Validation failures are sent back for repairs, up to maxRepairs times.
+// A manifest still failing validation is returned along with its error.
+func (c *Client) GenerateManifest(ctx context.Context, k *K8sContext, desc string, validate ValidateFunc, maxRepairs int) (*Generation, error) {
+	system := strings.ReplaceAll(generateSystemPrompt, "{{NS}}", k.Namespace)
+	mm := []Message{{Role: "user", Content: desc}}
+
+	var g Generation
+	for {
+		resp, err := c.Send(ctx, system, mm)
+		if err != nil {
+			return nil, err
+		}
+		g.Manifest = ParseManifest(resp.GetText())
+		if g.Manifest == "" {
+			return nil, ErrNoManifest
+		}
+		g.Err = validate(ctx, g.Manifest)
+		if g.Err == nil || g.Repairs >= maxRepairs || ctx.Err() != nil {
+			return &g, nil
+		}
+		g.Repairs++
+		mm = append(mm,
+			Message{Role: "assistant", Content: resp.GetText()},
+			Message{Role: "user", Content: fmt.Sprintf(repairPrompt, g.Err)},
+		)
+	}
+}
+
+// ParseManifest extracts a manifest from an answer, from its first code
+// block if any.
+func ParseManifest(s string) string {
+	var (
+		ll      []string
+		inBlock bool
+	)
+	for _, l := range strings.Split(s, "\n") {
+		t := strings.TrimSpace(l)
+		if strings.HasPrefix(t, "```") || strings.HasPrefix(t, "~~~") {
+			if inBlock {
+				break
+			}
+			inBlock, ll = true, ll[:0]
+			continue
+		}
+		ll = append(ll, l)
+	}
+	m := strings.TrimSpace(strings.Join(ll, "\n"))
+	if m == "" {
+		return ""
+	}
+
+	return m + "\n"
+}
+
+// AppliedResource tracks a resource applied from a manifest.
+type AppliedResource struct {
+	Path string
+
+	// Update tracks whether a dry-run applied resource already exists. Once
+	// really applied, resources always exist and are never told apart.
+	Update bool
+}
+
+// String returns the resource along with the change applied to it.
+func (r AppliedResource) String() string {
+	if r.Update {
+		return "update " + r.Path
+	}
+
+	return "create " + r.Path
+}
+
+// ApplyManifest server-side applies each resource of a manifest and returns
+// what was applied. Namespaced resources without a namespace land in ns. When
+// dryRun is set, resources are validated by the server but not persisted.
+func ApplyManifest(ctx context.Context, f dao.Factory, ns, manifest string, dryRun bool) ([]AppliedResource, error) {
+	oo, err := decodeManifest(manifest)
+	if err != nil {
+		return nil, err
+	}
+	if len(oo) == 0 {
+		return nil, errors.New("manifest holds no resources")
+	}
+	if client.IsAllNamespaces(ns) || ns == client.BlankNamespace {
+		ns = client.DefaultNamespace
+	}
+	opts := metav1.PatchOptions{FieldManager: manifestFieldManager}
+	if dryRun {
+		opts.DryRun = []string{metav1.DryRunAll}
+	}
+
+	var (
+		rr   = make([]AppliedResource, 0, len(oo))
+		errs []error
+	)
+	for _, o := range oo {
+		r, err := applyObject(ctx, f, ns, o, opts)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		rr = append(rr, r)
+	}
+
+	return rr, errors.Join(errs...)
+}
+
+// applyObject applies a resource. An existing resource comes back with its
+// resource version while a dry-run creation has none.
+func applyObject(ctx context.Context, f dao.Factory, ns string, o *unstructured.Unstructured, opts metav1.PatchOptions) (AppliedResource, error) {
+	var r AppliedResource
+	gv, err := schema.ParseGroupVersion(o.GetAPIVersion())
+	if err != nil {
+		return r, err
+	}
+	gvr, namespaced, ok := dao.MetaAccess.GVK2GVR(gv, o.GetKind())
+	if !ok {
+		return r, fmt.Errorf("unknown resource %s %s", o.GetAPIVersion(), o.GetKind())
+	}
+	if o.GetName() == "" {
+		return r, fmt.Errorf("%s: a name is required", o.GetKind())
+	}
+	if !namespaced {
+		o.SetNamespace("")
+	} else if o.GetNamespace() == "" {
+		o.SetNamespace(ns)
+	}
+	r.Path = gvr.R() + " " + client.FQN(o.GetNamespace(), o.GetName())
+
+	acc, err := dao.AccessorFor(f, gvr)
+	if err != nil {
+		return r, err
+	}
+	p, ok := acc.(dao.Patchable)
+	if !ok {
+		return r, fmt.Errorf("%s can not be applied", gvr)
+	}
+	bb, err := o.MarshalJSON()
+	if err != nil {
+		return r, err
+	}
+	res, err := p.Patch(ctx, fqnFor(gvr, o.GetNamespace(), o.GetName()), types.ApplyPatchType, bb, opts)
+	if err != nil {
+		return r, fmt.Errorf("%s: %w", r.Path, err)
+	}
+	m, err := meta.Accessor(res)
+	if err != nil {
+		return r, err
+	}
+	// A dry-run creation is never stored so it comes back without a version.
+	r.Update = len(opts.DryRun) > 0 && m.GetResourceVersion() != ""
+
+	return r, nil
+}
+
+func decodeManifest(manifest string) ([]*unstructured.Unstructured, error) {
+	r := kyaml.NewYAMLReader(bufio.NewReader(strings.NewReader(manifest)))
+	var oo []*unstructured.Unstructured
+	for {
+		doc, err := r.Read()
+		if errors.Is(err, io.EOF) {
+			return oo, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		bb, err := yaml.YAMLToJSON(doc)
+		if err != nil {
+			return nil, fmt.Errorf("invalid manifest: %w", err)
+		}
+		if strings.TrimSpace(string(bb)) == "null" {
+			continue
+		}
+		var o unstructured.Unstructured
+		if err := o.UnmarshalJSON(bb); err != nil {
+			return nil, fmt.Errorf("invalid manifest: %w", err)
+		}
+		oo = append(oo, &o)
+	}
+}
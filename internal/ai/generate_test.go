@@ -0,0 +1,163 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of K9s
+
+package ai_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/quentincherifi/c9s/internal/ai"
+	"github.com/quentincherifi/c9s/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseManifest(t *testing.T) {
+	uu := map[string]struct {
+		s, e string
+	}{
+		"empty": {},
+		"plain": {
+			s: "apiVersion: v1\nkind: Namespace\n",
+			e: "apiVersion: v1\nkind: Namespace\n",
+		},
+		"fenced": {
+			s: "Here you go:\n```yaml\napiVersion: v1\nkind: Namespace\n```\nEnjoy!",
+			e: "apiVersion: v1\nkind: Namespace\n",
+		},
+		"first-block": {
+			s: "```yaml\nkind: Namespace\n```\n```yaml\nkind: Pod\n```",
+			e: "kind: Namespace\n",
+		},
+		"blank-block": {
+			s: "```\n\n```",
+		},
+	}
+
+	for k := range uu {
+		u := uu[k]
+		t.Run(k, func(t *testing.T) {
+			assert.Equal(t, u.e, ai.ParseManifest(u.s))
+		})
+	}
+}
+
+func TestGenerateManifest(t *testing.T) {
+	uu := map[string]struct {
+		fails, maxRepairs int
+		repairs, calls    int
+		err               bool
+	}{
+		"valid": {
+			maxRepairs: 3,
+			calls:      1,
+		},
+		"repaired": {
+			fails:      2,
+			maxRepairs: 3,
+			repairs:    2,
+			calls:      3,
+		},
+		"exhausted": {
+			fails:      5,
+			maxRepairs: 2,
+			repairs:    2,
+			calls:      3,
+			err:        true,
+		},
+		"no-repairs": {
+			fails: 1,
+			calls: 1,
+			err:   true,
+		},
+	}
+
+	for k := range uu {
+		u := uu[k]
+		t.Run(k, func(t *testing.T) {
+			var reqs []ai.Request
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				var req ai.Request
+				require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+				reqs = append(reqs, req)
+				_, _ = w.Write(textResponse(t, "```yaml\nkind: Namespace\n```"))
+			}))
+			defer srv.Close()
+
+			c, err := ai.NewConfigClient(&config.AI{BaseURL: srv.URL}, "key")
+			require.NoError(t, err)
+			var n int
+			validate := func(_ context.Context, m string) error {
+				assert.Equal(t, "kind: Namespace\n", m)
+				if n++; n <= u.fails {
+					return errors.New("metadata.name: Required value")
+				}
+				return nil
+			}
+
+			g, err := c.GenerateManifest(context.Background(), &ai.K8sContext{Namespace: "fred"}, "a namespace", validate, u.maxRepairs)
+			require.NoError(t, err)
+			assert.Equal(t, "kind: Namespace\n", g.Manifest)
+			assert.Equal(t, u.repairs, g.Repairs)
+			assert.Equal(t, u.err, g.Err != nil)
+			require.Len(t, reqs, u.calls)
+			assert.Contains(t, reqs[0].System, "Current namespace: fred")
+			last := reqs[len(reqs)-1].Messages
+			assert.Len(t, last, 1+2*u.repairs)
+			if u.repairs > 0 {
+				assert.Contains(t, last[len(last)-1].Content, "metadata.name: Required value")
+			}
+		})
+	}
+}
+
+func TestGenerateManifestNone(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write(textResponse(t, "```\n```"))
+	}))
+	defer srv.Close()
+
+	c, err := ai.NewConfigClient(&config.AI{BaseURL: srv.URL}, "key")
+	require.NoError(t, err)
+	_, err = c.GenerateManifest(context.Background(), &ai.K8sContext{}, "nothing", nil, 3)
+	require.ErrorIs(t, err, ai.ErrNoManifest)
+}
+
+func TestApplyManifestInvalid(t *testing.T) {
+	uu := map[string]struct {
+		manifest, err string
+	}{
+		"empty": {
+			manifest: "---\n",
+			err:      "manifest holds no resources",
+		},
+		"not-yaml": {
+			manifest: "kind: [Pod\n",
+			err:      "invalid manifest",
+		},
+	}
+
+	for k := range uu {
+		u := uu[k]
+		t.Run(k, func(t *testing.T) {
+			_, err := ai.ApplyManifest(context.Background(), nil, "fred", u.manifest, true)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), u.err)
+		})
+	}
+}
+
+func textResponse(t *testing.T, text string) []byte {
+	bb, err := json.Marshal(map[string]any{
+		"content":     []map[string]string{{"type": "text", "text": text}},
+		"stop_reason": "end_turn",
+	})
+	require.NoError(t, err)
+
+	return bb
+}
@@ -0,0 +1,420 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of K9s
+
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/quentincherifi/c9s/internal"
+	"github.com/quentincherifi/c9s/internal/client"
+	"github.com/quentincherifi/c9s/internal/dao"
+	eventsv1 "k8s.io/api/events/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+const (
+	maxToolOutput   = 16 * 1024
+	maxListItems    = 200
+	defaultLogLines = 100
+	maxLogLines     = 500
+	logsTimeout     = 5 * time.Second
+)
+
+// Resolver resolves a resource alias to a GVR.
+type Resolver interface {
+	ResolveAlias(alias string) (*client.GVR, bool)
+}
+
+type resourceArgs struct {
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	Labels    string `json:"labelSelector"`
+}
+
+type logsArgs struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	Container string `json:"container"`
+	Lines     int64  `json:"lines"`
+	Previous  bool   `json:"previous"`
+}
+
+const (
+	resourceSchema = `{
+  "type": "object",
+  "properties": {
+    "kind": {"type": "string", "description": "Resource name, short name or alias, e.g. pods, deploy, svc, apps/v1/deployments"},
+    "namespace": {"type": "string", "description": "Namespace. Omit for cluster scoped resources"},
+    "name": {"type": "string", "description": "Resource name"}
+  },
+  "required": ["kind", "name"]
+}`
+
+	listSchema = `{
+  "type": "object",
+  "properties": {
+    "kind": {"type": "string", "description": "Resource name, short name or alias, e.g. pods, deploy, svc"},
+    "namespace": {"type": "string", "description": "Namespace. Omit to list across all namespaces"},
+    "labelSelector": {"type": "string", "description": "Optional label selector, e.g. app=nginx"}
+  },
+  "required": ["kind"]
+}`
+
+	logsSchema = `{
+  "type": "object",
+  "properties": {
+    "namespace": {"type": "string"},
+    "name": {"type": "string", "description": "Pod name"},
+    "container": {"type": "string", "description": "Container name. Defaults to the pod default container"},
+    "lines": {"type": "integer", "description": "Number of trailing lines (max 500)"},
+    "previous": {"type": "boolean", "description": "Fetch logs from the previous container instance"}
+  },
+  "required": ["namespace", "name"]
+}`
+
+	eventsSchema = `{
+  "type": "object",
+  "properties": {
+    "namespace": {"type": "string"},
+    "kind": {"type": "string", "description": "Optional kind of the involved object, e.g. Pod"},
+    "name": {"type": "string", "description": "Optional name of the involved object"}
+  },
+  "required": ["namespace"]
+}`
+)
+
+const (
+	listResourcesTool    = "list_resources"
+	getResourceTool      = "get_resource"
+	describeResourceTool = "describe_resource"
+	getLogsTool          = "get_logs"
+	getEventsTool        = "get_events"
+)
+
+// NewK8sToolbox returns read-only tools backed by the given factory.
+func NewK8sToolbox(f dao.Factory, r Resolver) *Toolbox {
+	k := k8sTools{factory: f, resolver: r}
+	tb := NewToolbox()
+	tb.Register(Tool{
+		Name:        listResourcesTool,
+		Description: "Lists Kubernetes resources of a given kind with their namespace and name.",
+		InputSchema: json.RawMessage(listSchema),
+	}, k.list)
+	tb.Register(Tool{
+		Name:        getResourceTool,
+		Description: "Returns the YAML manifest of a Kubernetes resource. Secret contents are not available.",
+		InputSchema: json.RawMessage(resourceSchema),
+	}, k.get)
+	tb.Register(Tool{
+		Name:        describeResourceTool,
+		Description: "Returns kubectl describe output for a Kubernetes resource, including recent events.",
+		InputSchema: json.RawMessage(resourceSchema),
+	}, k.describe)
+	tb.Register(Tool{
+		Name:        getLogsTool,
+		Description: "Returns the trailing log lines of a pod container.",
+		InputSchema: json.RawMessage(logsSchema),
+	}, k.logs)
+	tb.Register(Tool{
+		Name:        getEventsTool,
+		Description: "Lists recent events in a namespace, optionally filtered by involved object.",
+		InputSchema: json.RawMessage(eventsSchema),
+	}, k.events)
+
+	return tb
+}
+
+type k8sTools struct {
+	factory  dao.Factory
+	resolver Resolver
+}
+
+func (k k8sTools) resolve(kind string) (*client.GVR, error) {
+	return ResolveKind(k.resolver, kind)
+}
+
+// ResolveKind returns the resource matching a kind, alias or resource name.
+func ResolveKind(r Resolver, kind string) (*client.GVR, error) {
+	if kind == "" {
+		return nil, errors.New("a resource kind is required")
+	}
+	if r != nil {
+		if gvr, ok := r.ResolveAlias(kind); ok {
+			return gvr, nil
+		}
+	}
+	if gvr := dao.MetaAccess.Lookup(kind); gvr != client.NoGVR {
+		return gvr, nil
+	}
+
+	return nil, fmt.Errorf("unknown resource kind %q", kind)
+}
+
+func fqnFor(gvr *client.GVR, ns, n string) string {
+	if ok, err := dao.MetaAccess.IsNamespaced(gvr); err == nil && !ok {
+		ns = client.ClusterScope
+	}
+
+	return client.FQN(ns, n)
+}
+
+func (k k8sTools) canI(ns string, gvr *client.GVR, n string, verbs []string) error {
+	conn := k.factory.Client()
+	if conn == nil {
+		return errors.New("no cluster connection")
+	}
+	auth, err := conn.CanI(ns, gvr, n, verbs)
+	if err != nil {
+		return err
+	}
+	if !auth {
+		return fmt.Errorf("user is not authorized to %s %s", strings.Join(verbs, "/"), gvr)
+	}
+
+	return nil
+}
+
+func (k k8sTools) list(_ context.Context, in json.RawMessage) (string, error) {
+	var args resourceArgs
+	if err := json.Unmarshal(in, &args); err != nil {
+		return "", err
+	}
+	gvr, err := k.resolve(args.Kind)
+	if err != nil {
+		return "", err
+	}
+	ns := args.Namespace
+	if ns == "" {
+		ns = client.BlankNamespace
+	}
+	if err := k.canI(ns, gvr, "", client.ListAccess); err != nil {
+		return "", err
+	}
+	sel := labels.Everything()
+	if args.Labels != "" {
+		if sel, err = labels.Parse(args.Labels); err != nil {
+			return "", err
+		}
+	}
+	oo, err := k.factory.List(gvr, ns, true, sel)
+	if err != nil {
+		return "", err
+	}
+
+	names := make([]string, 0, len(oo))
+	for _, o := range oo {
+		m, err := meta.Accessor(o)
+		if err != nil {
+			continue
+		}
+		names = append(names, client.FQN(m.GetNamespace(), m.GetName()))
+	}
+	sort.Strings(names)
+	if len(names) > maxListItems {
+		names = append(names[:maxListItems], fmt.Sprintf("... %d more", len(names)-maxListItems))
+	}
+	if len(names) == 0 {
+		return fmt.Sprintf("no %s found", gvr.R()), nil
+	}
+
+	return strings.Join(names, "\n"), nil
+}
+
+func (k k8sTools) get(_ context.Context, in json.RawMessage) (string, error) {
+	var args resourceArgs
+	if err := json.Unmarshal(in, &args); err != nil {
+		return "", err
+	}
+	gvr, err := k.resolve(args.Kind)
+	if err != nil {
+		return "", err
+	}
+	if gvr == client.SecGVR {
+		return "", ErrSecretContent
+	}
+	if err := k.canI(args.Namespace, gvr, args.Name, client.GetAccess); err != nil {
+		return "", err
+	}
+	_, raw, err := FetchResource(k.factory, gvr, fqnFor(gvr, args.Namespace, args.Name))
+	if err != nil {
+		return "", err
+	}
+
+	return TrimText(raw, maxToolOutput), nil
+}
+
+func (k k8sTools) describe(_ context.Context, in json.RawMessage) (string, error) {
+	var args resourceArgs
+	if err := json.Unmarshal(in, &args); err != nil {
+		return "", err
+	}
+	gvr, err := k.resolve(args.Kind)
+	if err != nil {
+		return "", err
+	}
+	if err := k.canI(args.Namespace, gvr, args.Name, client.GetAccess); err != nil {
+		return "", err
+	}
+	raw, err := dao.Describe(k.factory.Client(), gvr, fqnFor(gvr, args.Namespace, args.Name))
+	if err != nil {
+		return "", err
+	}
+
+	return TrimText(raw, maxToolOutput), nil
+}
+
+func (k k8sTools) logs(ctx context.Context, in json.RawMessage) (string, error) {
+	var args logsArgs
+	if err := json.Unmarshal(in, &args); err != nil {
+		return "", err
+	}
+	if err := k.canI(args.Namespace, client.NewGVR(client.PodGVR.String()+":log"), args.Name, client.GetAccess); err != nil {
+		return "", err
+	}
+	if args.Lines <= 0 {
+		args.Lines = defaultLogLines
+	}
+	args.Lines = min(args.Lines, maxLogLines)
+
+	logs, err := tailLogs(ctx, k.factory, &dao.LogOptions{
+		Path:      client.FQN(args.Namespace, args.Name),
+		Container: args.Container,
+		Lines:     args.Lines,
+		Previous:  args.Previous,
+	})
+	if err != nil {
+		return "", err
+	}
+	if logs == "" {
+		return "no log lines found", nil
+	}
+
+	return TrimText(logs, maxToolOutput), nil
+}
+
+// tailLogs collects the last lines of a pod logs.
+func tailLogs(ctx context.Context, f dao.Factory, opts *dao.LogOptions) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, logsTimeout)
+	defer cancel()
+	ctx = context.WithValue(ctx, internal.KeyFactory, f)
+
+	var po dao.Pod
+	po.Init(f, client.PodGVR)
+	cc, err := po.TailLogs(ctx, opts)
+	if err != nil {
+		return "", err
+	}
+
+	var sb strings.Builder
+	for _, c := range cc {
+		drainLogs(ctx, c, &sb)
+	}
+
+	return sb.String(), nil
+}
+
+func drainLogs(ctx context.Context, c dao.LogChan, sb *strings.Builder) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case item, ok := <-c:
+			if !ok || item == dao.ItemEOF {
+				return
+			}
+			if item.Container != "" && !item.SingleContainer {
+				sb.WriteString("[" + item.Container + "] ")
+			}
+			sb.Write(item.Bytes)
+			if len(item.Bytes) > 0 && item.Bytes[len(item.Bytes)-1] != '\n' {
+				sb.WriteString("\n")
+			}
+		}
+	}
+}
+
+func (k k8sTools) events(_ context.Context, in json.RawMessage) (string, error) {
+	var args resourceArgs
+	if err := json.Unmarshal(in, &args); err != nil {
+		return "", err
+	}
+	if err := k.canI(args.Namespace, client.EvGVR, "", client.ListAccess); err != nil {
+		return "", err
+	}
+	oo, err := k.factory.List(client.EvGVR, args.Namespace, true, labels.Everything())
+	if err != nil {
+		return "", err
+	}
+	ee := FilterEvents(oo, args.Kind, args.Name)
+	if len(ee) == 0 {
+		return "no events found", nil
+	}
+
+	return TrimText(strings.Join(ee, "\n"), maxToolOutput), nil
+}
+
+// FilterEvents renders events matching an involved object kind and name,
+// most recent first. Blank kind or name match all objects.
+func FilterEvents(oo []runtime.Object, kind, name string) []string {
+	type entry struct {
+		at   time.Time
+		line string
+	}
+	ee := make([]entry, 0, len(oo))
+	for _, o := range oo {
+		u, ok := o.(*unstructured.Unstructured)
+		if !ok {
+			continue
+		}
+		var ev eventsv1.Event
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, &ev); err != nil {
+			continue
+		}
+		if kind != "" && !strings.EqualFold(ev.Regarding.Kind, kind) {
+			continue
+		}
+		if name != "" && ev.Regarding.Name != name {
+			continue
+		}
+		at := ev.EventTime.Time
+		if ev.Series != nil {
+			at = ev.Series.LastObservedTime.Time
+		}
+		if at.IsZero() {
+			at = ev.DeprecatedLastTimestamp.Time
+		}
+		if at.IsZero() {
+			at = ev.CreationTimestamp.Time
+		}
+		ee = append(ee, entry{
+			at: at,
+			line: fmt.Sprintf("%s %s %s %s/%s: %s",
+				at.UTC().Format(time.RFC3339),
+				ev.Type,
+				ev.Reason,
+				ev.Regarding.Kind,
+				ev.Regarding.Name,
+				strings.TrimSpace(ev.Note),
+			),
+		})
+	}
+	sort.SliceStable(ee, func(i, j int) bool { return ee[i].at.After(ee[j].at) })
+
+	ll := make([]string, 0, len(ee))
+	for _, e := range ee {
+		ll = append(ll, e.line)
+	}
+
+	return ll
+}
@@ -0,0 +1,66 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of K9s
+
+package ai
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// LogQuestion asks for a summary of the log excerpt found in the context.
+const LogQuestion = "Summarize the attached logs. List the distinct errors, point out the first failure " +
+	"and explain its most likely cause. Cite the relevant lines as L<number>."
+
+var lineRefRX = regexp.MustCompile(`\bL(\d+)\b`)
+
+// NumberLines keeps the trailing lines fitting the given line count and byte
+// size and prefixes each with its 1 based position in ll so the model may
+// cite them. Non positive limits mean no cap. It returns the excerpt along
+// with the number of lines kept.
+func NumberLines(ll []string, maxLines, size int) (string, int) {
+	start := 0
+	if maxLines > 0 && len(ll) > maxLines {
+		start = len(ll) - maxLines
+	}
+
+	nn := make([]string, 0, len(ll)-start)
+	total := 0
+	for i := len(ll) - 1; i >= start; i-- {
+		l := fmt.Sprintf("L%d %s", i+1, ll[i])
+		if size > 0 && total+len(l)+1 > size {
+			break
+		}
+		total += len(l) + 1
+		nn = append(nn, l)
+	}
+	for i, j := 0, len(nn)-1; i < j; i, j = i+1, j-1 {
+		nn[i], nn[j] = nn[j], nn[i]
+	}
+
+	return strings.Join(nn, "\n"), len(nn)
+}
+
+// LineRefs returns the distinct line references, i.e. L42, cited in a text
+// in order of appearance.
+func LineRefs(s string) []int {
+	var (
+		refs []int
+		seen = make(map[int]struct{})
+	)
+	for _, m := range lineRefRX.FindAllStringSubmatch(s, -1) {
+		n, err := strconv.Atoi(m[1])
+		if err != nil || n <= 0 {
+			continue
+		}
+		if _, ok := seen[n]; ok {
+			continue
+		}
+		seen[n] = struct{}{}
+		refs = append(refs, n)
+	}
+
+	return refs
+}
@@ -0,0 +1,74 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of K9s
+
+package ai_test
+
+import (
+	"testing"
+
+	"github.com/quentincherifi/c9s/internal/ai"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNumberLines(t *testing.T) {
+	ll := []string{"boot", "ready", "oops", "crash"}
+
+	uu := map[string]struct {
+		lines, size int
+		e           string
+		n           int
+	}{
+		"all": {
+			e: "L1 boot\nL2 ready\nL3 oops\nL4 crash",
+			n: 4,
+		},
+		"lines": {
+			lines: 2,
+			e:     "L3 oops\nL4 crash",
+			n:     2,
+		},
+		"size": {
+			size: 18,
+			e:    "L3 oops\nL4 crash",
+			n:    2,
+		},
+		"too-small": {
+			size: 2,
+		},
+	}
+
+	for k := range uu {
+		u := uu[k]
+		t.Run(k, func(t *testing.T) {
+			s, n := ai.NumberLines(ll, u.lines, u.size)
+			assert.Equal(t, u.e, s)
+			assert.Equal(t, u.n, n)
+		})
+	}
+}
+
+func TestLineRefs(t *testing.T) {
+	uu := map[string]struct {
+		s string
+		e []int
+	}{
+		"none": {
+			s: "All good. HTML5 is fine.",
+		},
+		"many": {
+			s: "First failure at L12, repeated on L40 and (L12). See L0 and xL3.",
+			e: []int{12, 40},
+		},
+		"ranges": {
+			s: "Lines L3-L5 show the panic.",
+			e: []int{3, 5},
+		},
+	}
+
+	for k := range uu {
+		u := uu[k]
+		t.Run(k, func(t *testing.T) {
+			assert.Equal(t, u.e, ai.LineRefs(u.s))
+		})
+	}
+}
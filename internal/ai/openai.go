@@ -0,0 +1,348 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of K9s
+
+package ai
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+const (
+	openAIBaseURL = "https://api.openai.com/v1"
+	openAIDone    = "[DONE]"
+
+	// maxToolCalls bounds how far ahead of the known calls a streamed tool
+	// call index may point.
+	maxToolCalls = 64
+)
+
+var _ Provider = (*openAI)(nil)
+
+// openAI talks to an OpenAI compatible chat completions API, as exposed by
+// OpenAI, Ollama, vLLM or LiteLLM.
+type openAI struct {
+	transport
+
+	apiKey string
+}
+
+type oaiRequest struct {
+	Model         string        `json:"model"`
+	MaxTokens     int           `json:"max_tokens,omitempty"`
+	Temperature   *float64      `json:"temperature,omitempty"`
+	Messages      []oaiMessage  `json:"messages"`
+	Tools         []oaiTool     `json:"tools,omitempty"`
+	Stream        bool          `json:"stream,omitempty"`
+	StreamOptions *oaiStreamOpt `json:"stream_options,omitempty"`
+}
+
+type oaiStreamOpt struct {
+	IncludeUsage bool `json:"include_usage"`
+}
+
+type oaiMessage struct {
+	Role       string        `json:"role"`
+	Content    string        `json:"content"`
+	ToolCalls  []oaiToolCall `json:"tool_calls,omitempty"`
+	ToolCallID string        `json:"tool_call_id,omitempty"`
+}
+
+type oaiTool struct {
+	Type     string      `json:"type"`
+	Function oaiFunction `json:"function"`
+}
+
+type oaiFunction struct {
+	Name        string          `json:"name"`
+	Description string          `json:"description,omitempty"`
+	Parameters  json.RawMessage `json:"parameters,omitempty"`
+	Arguments   string          `json:"arguments,omitempty"`
+}
+
+type oaiToolCall struct {
+	Index    int         `json:"index,omitempty"`
+	ID       string      `json:"id,omitempty"`
+	Type     string      `json:"type,omitempty"`
+	Function oaiFunction `json:"function"`
+}
+
+type oaiUsage struct {
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
+}
+
+type oaiChoice struct {
+	Message      *oaiMessage `json:"message,omitempty"`
+	Delta        *oaiMessage `json:"delta,omitempty"`
+	FinishReason string      `json:"finish_reason"`
+}
+
+type oaiResponse struct {
+	ID      string      `json:"id"`
+	Model   string      `json:"model"`
+	Choices []oaiChoice `json:"choices"`
+	Usage   *oaiUsage   `json:"usage,omitempty"`
+	Error   *oaiError   `json:"error,omitempty"`
+}
+
+type oaiError struct {
+	Type    string `json:"type"`
+	Message string `json:"message"`
+}
+
+// Name returns the provider name.
+func (*openAI) Name() string {
+	return "openai"
+}
+
+// Send issues a request and returns the complete response.
+func (o *openAI) Send(ctx context.Context, req Request) (*Response, error) {
+	resp, err := o.post(ctx, toOpenAI(req, false))
+	if err != nil {
+		return nil, err
+	}
+
+	var res oaiResponse
+	if err := decode(resp, &res); err != nil {
+		return nil, err
+	}
+	if len(res.Choices) == 0 || res.Choices[0].Message == nil {
+		return nil, errors.New("API error: no choices returned")
+	}
+	c := res.Choices[0]
+
+	return fromOpenAI(&res, c.Message.Content, c.Message.ToolCalls, c.FinishReason), nil
+}
+
+// Stream issues a streaming request.
+func (o *openAI) Stream(ctx context.Context, req Request, fn StreamFunc) (*Response, error) {
+	resp, err := o.post(ctx, toOpenAI(req, true))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	return readOpenAIStream(resp.Body, fn)
+}
+
+func (o *openAI) post(ctx context.Context, req oaiRequest) (*http.Response, error) {
+	hh := http.Header{}
+	if o.apiKey != "" {
+		hh.Set("Authorization", "Bearer "+o.apiKey)
+	}
+	if req.Stream {
+		hh.Set("Accept", "text/event-stream")
+	}
+
+	return o.transport.post(ctx, "/chat/completions", req, hh, openAIErr)
+}
+
+func openAIErr(bb []byte) (typ, msg string, ok bool) {
+	var res oaiResponse
+	if err := json.Unmarshal(bb, &res); err != nil || res.Error == nil {
+		return "", "", false
+	}
+
+	return res.Error.Type, res.Error.Message, true
+}
+
+// toOpenAI converts a messages request to a chat completions request.
+// Tool results are carried as individual tool role messages.
+func toOpenAI(req Request, stream bool) oaiRequest {
+	r := oaiRequest{
+		Model:       req.Model,
+		MaxTokens:   req.MaxTokens,
+		Temperature: req.Temperature,
+		Messages:    make([]oaiMessage, 0, len(req.Messages)+1),
+		Stream:      stream,
+	}
+	if stream {
+		r.StreamOptions = &oaiStreamOpt{IncludeUsage: true}
+	}
+	if req.System != "" {
+		r.Messages = append(r.Messages, oaiMessage{Role: "system", Content: req.System})
+	}
+	for _, m := range req.Messages {
+		if len(m.Blocks) == 0 {
+			r.Messages = append(r.Messages, oaiMessage{Role: m.Role, Content: m.Content})
+			continue
+		}
+		msg := oaiMessage{Role: m.Role, Content: m.Text()}
+		for _, b := range m.Blocks {
+			switch b.Type {
+			case BlockToolUse:
+				msg.ToolCalls = append(msg.ToolCalls, oaiToolCall{
+					ID:   b.ID,
+					Type: "function",
+					Function: oaiFunction{
+						Name:      b.Name,
+						Arguments: string(b.Input),
+					},
+				})
+			case BlockToolResult:
+				r.Messages = append(r.Messages, oaiMessage{
+					Role:       "tool",
+					ToolCallID: b.ToolUseID,
+					Content:    b.Content,
+				})
+			}
+		}
+		if msg.Content != "" || len(msg.ToolCalls) > 0 {
+			r.Messages = append(r.Messages, msg)
+		}
+	}
+	for _, t := range req.Tools {
+		r.Tools = append(r.Tools, oaiTool{
+			Type: "function",
+			Function: oaiFunction{
+				Name:        t.Name,
+				Description: t.Description,
+				Parameters:  t.InputSchema,
+			},
+		})
+	}
+
+	return r
+}
+
+// fromOpenAI converts a chat completion to a messages response.
+func fromOpenAI(res *oaiResponse, text string, calls []oaiToolCall, finish string) *Response {
+	r := Response{
+		ID:         res.ID,
+		Type:       "message",
+		Role:       "assistant",
+		Model:      res.Model,
+		StopReason: stopReason(finish),
+	}
+	if text != "" {
+		r.Content = append(r.Content, ContentBlock{Type: BlockText, Text: text})
+	}
+	for i, c := range calls {
+		in := json.RawMessage(c.Function.Arguments)
+		if len(in) == 0 {
+			in = json.RawMessage("{}")
+		}
+		// Some local servers omit call ids, which tool results must reference.
+		id := c.ID
+		if id == "" {
+			id = fmt.Sprintf("call_%d", i)
+		}
+		r.Content = append(r.Content, ContentBlock{
+			Type:  BlockToolUse,
+			ID:    id,
+			Name:  c.Function.Name,
+			Input: in,
+		})
+	}
+	// Some local servers report a plain stop when calling tools.
+	if len(calls) > 0 && r.StopReason != "max_tokens" {
+		r.StopReason = stopToolUse
+	}
+	if res.Usage != nil {
+		r.Usage = Usage{
+			InputTokens:  res.Usage.PromptTokens,
+			OutputTokens: res.Usage.CompletionTokens,
+		}
+	}
+
+	return &r
+}
+
+func stopReason(finish string) string {
+	switch finish {
+	case "tool_calls", "function_call":
+		return stopToolUse
+	case "length":
+		return "max_tokens"
+	case "":
+		return ""
+	default:
+		return "end_turn"
+	}
+}
+
+func readOpenAIStream(r io.Reader, fn StreamFunc) (*Response, error) {
+	var (
+		res     oaiResponse
+		text    strings.Builder
+		calls   []oaiToolCall
+		finish  string
+		scanner = bufio.NewScanner(r)
+	)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxEventSize)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if !strings.HasPrefix(line, "data:") {
+			continue
+		}
+		data := strings.TrimSpace(line[len("data:"):])
+		if data == openAIDone {
+			return fromOpenAI(&res, text.String(), calls, finish), nil
+		}
+		var chunk oaiResponse
+		if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+			return fromOpenAI(&res, text.String(), calls, finish), fmt.Errorf("failed to parse stream event: %w", err)
+		}
+		if chunk.Error != nil {
+			return fromOpenAI(&res, text.String(), calls, finish), &APIError{Type: chunk.Error.Type, Message: chunk.Error.Message}
+		}
+		if res.ID == "" {
+			res.ID, res.Model = chunk.ID, chunk.Model
+		}
+		if chunk.Usage != nil {
+			res.Usage = chunk.Usage
+		}
+		for _, c := range chunk.Choices {
+			if c.FinishReason != "" {
+				finish = c.FinishReason
+			}
+			if c.Delta == nil {
+				continue
+			}
+			if c.Delta.Content != "" {
+				text.WriteString(c.Delta.Content)
+				if fn != nil {
+					fn(c.Delta.Content)
+				}
+			}
+			var err error
+			if calls, err = mergeToolCalls(calls, c.Delta.ToolCalls); err != nil {
+				return fromOpenAI(&res, text.String(), calls, finish), err
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fromOpenAI(&res, text.String(), calls, finish), fmt.Errorf("failed to read stream: %w", err)
+	}
+
+	return fromOpenAI(&res, text.String(), calls, finish), errors.New("stream closed before message completed")
+}
+
+// mergeToolCalls accumulates streamed tool call fragments by index.
+func mergeToolCalls(calls, deltas []oaiToolCall) ([]oaiToolCall, error) {
+	for _, d := range deltas {
+		if d.Index < 0 || d.Index > len(calls)+maxToolCalls {
+			return calls, fmt.Errorf("invalid tool call index %d in stream event", d.Index)
+		}
+		for len(calls) <= d.Index {
+			calls = append(calls, oaiToolCall{Index: len(calls)})
+		}
+		c := &calls[d.Index]
+		if d.ID != "" {
+			c.ID = d.ID
+		}
+		if d.Function.Name != "" {
+			c.Function.Name = d.Function.Name
+		}
+		c.Function.Arguments += d.Function.Arguments
+	}
+
+	return calls, nil
+}
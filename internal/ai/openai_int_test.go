@@ -0,0 +1,133 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of K9s
+
+package ai
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestToOpenAI(t *testing.T) {
+	req := Request{
+		Model:     "llama3",
+		MaxTokens: 100,
+		System:    "be brief",
+		Messages: []Message{
+			{Role: "user", Content: "why?"},
+			{Role: "assistant", Blocks: []ContentBlock{
+				{Type: BlockText, Text: "Checking"},
+				{Type: BlockToolUse, ID: "c1", Name: "get_logs", Input: json.RawMessage(`{"name":"p1"}`)},
+			}},
+			{Role: "user", Blocks: []ContentBlock{
+				{Type: BlockToolResult, ToolUseID: "c1", Content: "boom"},
+			}},
+		},
+		Tools: []Tool{{Name: "get_logs", Description: "logs", InputSchema: json.RawMessage(`{"type":"object"}`)}},
+	}
+
+	bb, err := json.Marshal(toOpenAI(req, true))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+  "model": "llama3",
+  "max_tokens": 100,
+  "stream": true,
+  "stream_options": {"include_usage": true},
+  "messages": [
+    {"role": "system", "content": "be brief"},
+    {"role": "user", "content": "why?"},
+    {"role": "assistant", "content": "Checking", "tool_calls": [
+      {"id": "c1", "type": "function", "function": {"name": "get_logs", "arguments": "{\"name\":\"p1\"}"}}
+    ]},
+    {"role": "tool", "content": "boom", "tool_call_id": "c1"}
+  ],
+  "tools": [
+    {"type": "function", "function": {"name": "get_logs", "description": "logs", "parameters": {"type": "object"}}}
+  ]
+}`, string(bb))
+}
+
+func TestToOpenAITemperature(t *testing.T) {
+	temp := 0.2
+	bb, err := json.Marshal(toOpenAI(Request{Model: "llama3", Temperature: &temp}, false))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"model": "llama3", "temperature": 0.2, "messages": []}`, string(bb))
+
+	bb, err = json.Marshal(toOpenAI(Request{Model: "llama3"}, false))
+	require.NoError(t, err)
+	assert.NotContains(t, string(bb), "temperature")
+}
+
+const oaiStream = `data: {"id":"c-1","model":"llama3","choices":[{"index":0,"delta":{"role":"assistant","content":"Hel"}}]}
+
+data: {"id":"c-1","model":"llama3","choices":[{"index":0,"delta":{"content":"lo"}}]}
+
+data: {"id":"c-1","model":"llama3","choices":[{"index":0,"delta":{"tool_calls":[{"index":0,"id":"call_a","type":"function","function":{"name":"get_logs","arguments":""}}]}}]}
+
+data: {"id":"c-1","model":"llama3","choices":[{"index":0,"delta":{"tool_calls":[{"index":0,"function":{"arguments":"{\"name\":"}}]}}]}
+
+data: {"id":"c-1","model":"llama3","choices":[{"index":0,"delta":{"tool_calls":[{"index":0,"function":{"arguments":"\"p1\"}"}}]}}]}
+
+data: {"id":"c-1","model":"llama3","choices":[{"index":0,"delta":{},"finish_reason":"tool_calls"}]}
+
+data: {"id":"c-1","model":"llama3","choices":[],"usage":{"prompt_tokens":10,"completion_tokens":5}}
+
+data: [DONE]
+`
+
+func TestReadOpenAIStream(t *testing.T) {
+	var deltas []string
+	res, err := readOpenAIStream(strings.NewReader(oaiStream), func(s string) {
+		deltas = append(deltas, s)
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"Hel", "lo"}, deltas)
+	assert.Equal(t, "Hello", res.GetText())
+	assert.Equal(t, stopToolUse, res.StopReason)
+	assert.Equal(t, Usage{InputTokens: 10, OutputTokens: 5}, res.Usage)
+	uu := res.ToolUses()
+	require.Len(t, uu, 1)
+	assert.Equal(t, "call_a", uu[0].ID)
+	assert.Equal(t, "get_logs", uu[0].Name)
+	assert.JSONEq(t, `{"name":"p1"}`, string(uu[0].Input))
+}
+
+func TestReadOpenAIStreamErrors(t *testing.T) {
+	uu := map[string]struct {
+		in, err string
+	}{
+		"api-error": {
+			in:  `data: {"error":{"type":"server_error","message":"boom"}}` + "\n",
+			err: "API error: server_error - boom",
+		},
+		"truncated": {
+			in:  `data: {"choices":[{"delta":{"content":"Hel"}}]}` + "\n",
+			err: "stream closed before message completed",
+		},
+		"bad-json": {
+			in:  "data: {nope\n",
+			err: "failed to parse stream event",
+		},
+		"negative-tool-index": {
+			in:  `data: {"choices":[{"delta":{"tool_calls":[{"index":-1,"function":{"name":"get_logs"}}]}}]}` + "\n",
+			err: "invalid tool call index -1",
+		},
+		"huge-tool-index": {
+			in:  `data: {"choices":[{"delta":{"tool_calls":[{"index":100000000,"function":{"name":"get_logs"}}]}}]}` + "\n",
+			err: "invalid tool call index 100000000",
+		},
+	}
+
+	for k := range uu {
+		u := uu[k]
+		t.Run(k, func(t *testing.T) {
+			_, err := readOpenAIStream(strings.NewReader(u.in), nil)
+			assert.ErrorContains(t, err, u.err)
+		})
+	}
+}
@@ -0,0 +1,69 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of K9s
+
+package ai
+
+import (
+	"errors"
+
+	"github.com/quentincherifi/c9s/internal/config/data"
+)
+
+var (
+	// ErrPolicyDisabled indicates the context policy disables the assistant.
+	ErrPolicyDisabled = errors.New("AI assistant is disabled for this context by policy")
+
+	// ErrPolicyContent indicates the context policy withholds resource contents.
+	ErrPolicyContent = errors.New("resource contents may not be shared in this context by policy")
+
+	// ErrPolicyLogs indicates the context policy withholds logs.
+	ErrPolicyLogs = errors.New("logs may not be shared in this context by policy")
+)
+
+// Restrict drops the context information a sharing policy withholds.
+func (k *K8sContext) Restrict(p *data.AIPolicy) {
+	k.Withheld = ""
+	if !p.AllowsContent() {
+		k.ResourceYAML, k.Events, k.Tree = "", "", ""
+		k.RowsSource, k.Rows = "", ""
+		k.Withheld = "resource manifests, events, table rows, dependency trees and logs"
+	}
+	if !p.AllowsLogs() {
+		k.LogSource, k.Logs = "", ""
+		if k.Withheld == "" {
+			k.Withheld = "logs"
+		}
+	}
+}
+
+// Restrict drops the tools a sharing policy withholds. Only listing resource
+// names is allowed when contents are withheld.
+func (t *Toolbox) Restrict(p *data.AIPolicy) {
+	if t == nil {
+		return
+	}
+	if !p.AllowsContent() {
+		t.Remove(getResourceTool, describeResourceTool, getLogsTool, getEventsTool, ProposeActionTool)
+		return
+	}
+	if !p.AllowsLogs() {
+		t.Remove(getLogsTool)
+	}
+}
+
+// Restrict drops the evidence a sharing policy withholds. Diagnosing is not
+// possible when resource contents are withheld.
+func (e *Evidence) Restrict(p *data.AIPolicy) error {
+	if !p.AllowsContent() {
+		return ErrPolicyContent
+	}
+	if !p.AllowsLogs() {
+		e.PreviousLogs = nil
+		// Termination messages may hold the container last log lines.
+		for i := range e.Terminations {
+			e.Terminations[i].Message = ""
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,150 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of K9s
+
+package ai_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/quentincherifi/c9s/internal/ai"
+	"github.com/quentincherifi/c9s/internal/config/data"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestK8sContextRestrict(t *testing.T) {
+	full := ai.K8sContext{
+		SelectedResource: "default/fred",
+		ResourceYAML:     "kind: Pod",
+		Events:           "BackOff",
+		Tree:             "pods default/fred [ok]",
+		RowsSource:       "1 marked rows",
+		Rows:             "NAME\nfred",
+		LogSource:        "pods default/fred",
+		Logs:             "1 boom",
+	}
+
+	uu := map[string]struct {
+		share      string
+		yaml, logs string
+		withheld   string
+	}{
+		"all": {
+			share: data.AIShareAll,
+			yaml:  "kind: Pod",
+			logs:  "1 boom",
+		},
+		"no-logs": {
+			share:    data.AIShareNoLogs,
+			yaml:     "kind: Pod",
+			withheld: "logs",
+		},
+		"names": {
+			share:    data.AIShareNames,
+			withheld: "resource manifests, events, table rows, dependency trees and logs",
+		},
+	}
+
+	for k := range uu {
+		u := uu[k]
+		t.Run(k, func(t *testing.T) {
+			c := full
+			c.Restrict(&data.AIPolicy{Share: u.share})
+			assert.Equal(t, "default/fred", c.SelectedResource)
+			assert.Equal(t, u.yaml, c.ResourceYAML)
+			assert.Equal(t, u.logs, c.Logs)
+			assert.Equal(t, u.withheld, c.Withheld)
+
+			p, err := ai.BuildSystemPrompt(&c)
+			require.NoError(t, err)
+			if u.withheld == "" {
+				assert.NotContains(t, p, "data sharing policy")
+			} else {
+				assert.Contains(t, p, "withholds "+u.withheld)
+			}
+		})
+	}
+}
+
+func TestToolboxRestrict(t *testing.T) {
+	uu := map[string]struct {
+		share string
+		tools []string
+	}{
+		"all": {
+			share: data.AIShareAll,
+			tools: []string{"list_resources", "get_resource", "describe_resource", "get_logs", "get_events", ai.ProposeActionTool},
+		},
+		"no-logs": {
+			share: data.AIShareNoLogs,
+			tools: []string{"list_resources", "get_resource", "describe_resource", "get_events", ai.ProposeActionTool},
+		},
+		"names": {
+			share: data.AIShareNames,
+			tools: []string{"list_resources"},
+		},
+	}
+
+	for k := range uu {
+		u := uu[k]
+		t.Run(k, func(t *testing.T) {
+			tb := ai.NewK8sToolbox(nil, nil)
+			ai.RegisterActions(tb, nil, nil, func(*ai.Action) {})
+			tb.Restrict(&data.AIPolicy{Share: u.share})
+
+			nn := make([]string, 0, len(u.tools))
+			for _, tool := range tb.Tools() {
+				nn = append(nn, tool.Name)
+			}
+			assert.Equal(t, u.tools, nn)
+
+			if u.share == data.AIShareAll {
+				return
+			}
+			res := tb.Run(context.Background(), []ai.ContentBlock{{Type: ai.BlockToolUse, ID: "1", Name: "get_logs", Input: json.RawMessage(`{}`)}}, nil)
+			require.Len(t, res, 1)
+			assert.True(t, res[0].IsError)
+			assert.Contains(t, res[0].Content, "unknown tool")
+		})
+	}
+}
+
+func TestEvidenceRestrict(t *testing.T) {
+	uu := map[string]struct {
+		share string
+		err   error
+		logs  int
+		msg   string
+	}{
+		"all": {
+			share: data.AIShareAll,
+			logs:  1,
+			msg:   "boom",
+		},
+		"no-logs": {
+			share: data.AIShareNoLogs,
+		},
+		"names": {
+			share: data.AIShareNames,
+			err:   ai.ErrPolicyContent,
+			logs:  1,
+			msg:   "boom",
+		},
+	}
+
+	for k := range uu {
+		u := uu[k]
+		t.Run(k, func(t *testing.T) {
+			e := ai.Evidence{
+				Terminations: []ai.Termination{{State: "fred/c1 terminated with exit code 1 (Error)", Message: "boom"}},
+				PreviousLogs: []ai.ContainerLogs{{Pod: "fred", Container: "c1", Logs: "boom"}},
+			}
+			assert.ErrorIs(t, e.Restrict(&data.AIPolicy{Share: u.share}), u.err)
+			assert.Len(t, e.PreviousLogs, u.logs)
+			assert.Equal(t, "fred/c1 terminated with exit code 1 (Error)", e.Terminations[0].State)
+			assert.Equal(t, u.msg, e.Terminations[0].Message)
+		})
+	}
+}
@@ -0,0 +1,170 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of K9s
+
+package ai
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/quentincherifi/c9s/internal/config"
+)
+
+// Provider issues requests against a model API.
+type Provider interface {
+	// Name returns the provider name.
+	Name() string
+
+	// Send issues a request and returns the complete response.
+	Send(ctx context.Context, req Request) (*Response, error)
+
+	// Stream issues a streaming request, handing text deltas to fn as they
+	// arrive, and returns the assembled response.
+	Stream(ctx context.Context, req Request, fn StreamFunc) (*Response, error)
+}
+
+// NewProvider returns the provider described by the given configuration.
+func NewProvider(cfg *config.AI, apiKey string) (Provider, error) {
+	hc, err := newHTTPClient(cfg.TLS)
+	if err != nil {
+		return nil, err
+	}
+	t := transport{
+		baseURL:    strings.TrimSuffix(cfg.BaseURL, "/"),
+		headers:    cfg.Headers,
+		httpClient: hc,
+	}
+
+	switch cfg.GetProvider() {
+	case config.AIProviderAnthropic:
+		if t.baseURL == "" {
+			t.baseURL = anthropicBaseURL
+		}
+		return &anthropic{transport: t, apiKey: apiKey}, nil
+	case config.AIProviderOpenAI:
+		if t.baseURL == "" {
+			t.baseURL = openAIBaseURL
+		}
+		return &openAI{transport: t, apiKey: apiKey}, nil
+	default:
+		return nil, fmt.Errorf("unsupported AI provider %q", cfg.Provider)
+	}
+}
+
+func newHTTPClient(cfg *config.AITLS) (*http.Client, error) {
+	tr := http.DefaultTransport.(*http.Transport).Clone()
+	tr.ResponseHeaderTimeout = defaultTimeout
+	if cfg == nil {
+		return &http.Client{Transport: tr}, nil
+	}
+
+	tc := tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: cfg.InsecureSkipVerify, //nolint:gosec // opt-in for self-signed lab endpoints
+	}
+	if cfg.CAFile != "" {
+		pem, err := os.ReadFile(cfg.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read AI CA file: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in AI CA file %q", cfg.CAFile)
+		}
+		tc.RootCAs = pool
+	}
+	if cfg.CertFile != "" || cfg.KeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(cfg.CertFile, cfg.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("unable to load AI client certificate: %w", err)
+		}
+		tc.Certificates = []tls.Certificate{cert}
+	}
+	tr.TLSClientConfig = &tc
+
+	return &http.Client{Transport: tr}, nil
+}
+
+// transport tracks the HTTP settings shared by providers.
+type transport struct {
+	baseURL    string
+	headers    map[string]string
+	httpClient *http.Client
+}
+
+// errorFunc extracts the error type and message from a failed API response
+// body.
+type errorFunc func(body []byte) (typ, msg string, ok bool)
+
+// post issues a JSON request and checks the response status.
+func (t *transport) post(ctx context.Context, path string, payload any, hh http.Header, errFn errorFunc) (*http.Response, error) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, t.baseURL+path, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	for k, vv := range hh {
+		for _, v := range vv {
+			httpReq.Header.Add(k, v)
+		}
+	}
+	for k, v := range t.headers {
+		httpReq.Header.Set(k, v)
+	}
+
+	resp, err := t.httpClient.Do(httpReq)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, fmt.Errorf("%w: %w", ErrUnreachable, err)
+	}
+	if resp.StatusCode == http.StatusOK {
+		return resp, nil
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	apiErr := APIError{
+		StatusCode: resp.StatusCode,
+		Message:    string(respBody),
+		RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()),
+	}
+	if typ, msg, ok := errFn(respBody); ok {
+		apiErr.Type, apiErr.Message = typ, msg
+	}
+
+	return nil, &apiErr
+}
+
+// decode reads a JSON response body.
+func decode(resp *http.Response, v any) error {
+	defer resp.Body.Close()
+
+	bb, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+	if err := json.Unmarshal(bb, v); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	return nil
+}
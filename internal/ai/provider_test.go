@@ -0,0 +1,133 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of K9s
+
+package ai_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/quentincherifi/c9s/internal/ai"
+	"github.com/quentincherifi/c9s/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewProvider(t *testing.T) {
+	uu := map[string]struct {
+		cfg  config.AI
+		name string
+		err  string
+	}{
+		"default": {
+			name: "anthropic",
+		},
+		"openai": {
+			cfg:  config.AI{Provider: config.AIProviderOpenAI},
+			name: "openai",
+		},
+		"unknown": {
+			cfg: config.AI{Provider: "fred"},
+			err: `unsupported AI provider "fred"`,
+		},
+		"bad-ca": {
+			cfg: config.AI{TLS: &config.AITLS{CAFile: "/no/such/ca.pem"}},
+			err: "unable to read AI CA file",
+		},
+	}
+
+	for k := range uu {
+		u := uu[k]
+		t.Run(k, func(t *testing.T) {
+			p, err := ai.NewProvider(&u.cfg, "key")
+			if u.err != "" {
+				assert.ErrorContains(t, err, u.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, u.name, p.Name())
+		})
+	}
+}
+
+func TestProviderSend(t *testing.T) {
+	uu := map[string]struct {
+		provider, path, auth, body, text, err string
+		status                                int
+	}{
+		"anthropic": {
+			provider: config.AIProviderAnthropic,
+			path:     "/v1/messages",
+			body:     `{"content":[{"type":"text","text":"hi"}],"stop_reason":"end_turn"}`,
+			text:     "hi",
+		},
+		"anthropic-error": {
+			provider: config.AIProviderAnthropic,
+			path:     "/v1/messages",
+			status:   http.StatusTooManyRequests,
+			body:     `{"type":"error","error":{"type":"rate_limit_error","message":"slow down"}}`,
+			err:      "API error: rate_limit_error - slow down",
+		},
+		"openai": {
+			provider: config.AIProviderOpenAI,
+			path:     "/v1/chat/completions",
+			auth:     "Bearer key",
+			body:     `{"choices":[{"message":{"role":"assistant","content":"hi"},"finish_reason":"stop"}]}`,
+			text:     "hi",
+		},
+		"openai-error": {
+			provider: config.AIProviderOpenAI,
+			path:     "/v1/chat/completions",
+			status:   http.StatusBadRequest,
+			body:     `{"error":{"type":"invalid_request_error","message":"nope"}}`,
+			err:      "API error: invalid_request_error - nope",
+		},
+		"raw-error": {
+			provider: config.AIProviderOpenAI,
+			path:     "/v1/chat/completions",
+			status:   http.StatusBadGateway,
+			body:     `bad gateway`,
+			err:      "API error (status 502): bad gateway",
+		},
+	}
+
+	for k := range uu {
+		u := uu[k]
+		t.Run(k, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, u.path, r.URL.Path)
+				assert.Equal(t, "blee", r.Header.Get("X-Fred"))
+				if u.auth != "" {
+					assert.Equal(t, u.auth, r.Header.Get("Authorization"))
+				}
+				_, _ = io.Copy(io.Discard, r.Body)
+				if u.status != 0 {
+					w.WriteHeader(u.status)
+				}
+				_, _ = w.Write([]byte(u.body))
+			}))
+			defer srv.Close()
+
+			cfg := config.AI{
+				Provider: u.provider,
+				BaseURL:  srv.URL + "/v1/",
+				Headers:  map[string]string{"X-Fred": "blee"},
+				// Retries are covered in TestClientRetry.
+				MaxRetries: -1,
+			}
+			c, err := ai.NewConfigClient(&cfg, "key")
+			require.NoError(t, err)
+
+			res, err := c.Send(context.Background(), "", []ai.Message{{Role: "user", Content: "hello"}})
+			if u.err != "" {
+				assert.EqualError(t, err, u.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, u.text, res.GetText())
+		})
+	}
+}
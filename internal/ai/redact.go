@@ -0,0 +1,192 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of K9s
+
+package ai
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/quentincherifi/c9s/internal/config"
+)
+
+// Redacted replaces sensitive values in content sent to the model.
+const Redacted = "[REDACTED]"
+
+const sensitiveKeys = `password|passwd|secret|token|api[_-]?key|private[_-]?key|access[_-]?key|secret[_-]?key|auth[_-]?key|credentials?`
+
+var (
+	privateKeyRX = regexp.MustCompile(`(?s)-----BEGIN [A-Z ]*PRIVATE KEY-----.*?-----END [A-Z ]*PRIVATE KEY-----`)
+	jwtRX        = regexp.MustCompile(`\beyJ[A-Za-z0-9_-]{5,}\.eyJ[A-Za-z0-9_-]{5,}\.[A-Za-z0-9_-]{5,}`)
+	bearerRX     = regexp.MustCompile(`(?i)\bbearer\s+([A-Za-z0-9._~+/=-]{8,})`)
+	envYAMLRX    = regexp.MustCompile(`(?im)name:[ \t]*["']?[\w.-]*(?:` + sensitiveKeys + `)["']?[ \t]*\n[ \t]*value:[ \t]*(\S.*)$`)
+	envJSONRX    = regexp.MustCompile(`(?i)"name"\s*:\s*"[\w.-]*(?:` + sensitiveKeys + `)"\s*,\s*"value"\s*:\s*("(?:[^"\\]|\\.)*")`)
+	keyValueRX   = regexp.MustCompile(`(?i)\b[\w.-]*(?:` + sensitiveKeys + `)["']?[ \t]*[:=][ \t]*("(?:[^"\\]|\\.)*"|'[^']*'|[^\s,;&}"']+)`)
+	secretKindRX = regexp.MustCompile(`(?m)^kind:\s*Secret\s*$|"kind"\s*:\s*"Secret"`)
+	secretDataRX = regexp.MustCompile(`^(\s*)(?:data|stringData):\s*$`)
+	dataJSONRX   = regexp.MustCompile(`"(?:data|stringData)"\s*:\s*\{[^}]*\}`)
+	dataValueRX  = regexp.MustCompile(`:\s*("(?:[^"\\]|\\.)*")`)
+)
+
+// Redactor masks sensitive values before they leave the cluster.
+type Redactor struct {
+	rules []redactRule
+}
+
+type redactRule struct {
+	rx    *regexp.Regexp
+	group int
+}
+
+// NewRedactor returns a redactor with the built-in rules plus the given
+// custom rules. A custom rule masks its first capture group if any or the
+// whole match otherwise.
+func NewRedactor(custom []config.AIRedactRule) (*Redactor, error) {
+	r := Redactor{
+		rules: []redactRule{
+			{rx: privateKeyRX},
+			{rx: jwtRX},
+			{rx: bearerRX, group: 1},
+			{rx: envYAMLRX, group: 1},
+			{rx: envJSONRX, group: 1},
+			{rx: keyValueRX, group: 1},
+		},
+	}
+	for i, c := range custom {
+		rx, err := regexp.Compile(c.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid redact rule #%d %q: %w", i, c.Name, err)
+		}
+		rule := redactRule{rx: rx}
+		if rx.NumSubexp() > 0 {
+			rule.group = 1
+		}
+		r.rules = append(r.rules, rule)
+	}
+
+	return &r, nil
+}
+
+// Redact masks sensitive values and returns the number of values masked.
+func (r *Redactor) Redact(s string) (string, int) {
+	if r == nil || s == "" {
+		return s, 0
+	}
+
+	s, total := redactSecretData(s)
+	for _, rule := range r.rules {
+		var n int
+		s, n = mask(s, rule.rx, rule.group)
+		total += n
+	}
+
+	return s, total
+}
+
+// RedactRequest returns a copy of a request with sensitive values masked
+// along with the number of values masked.
+func (r *Redactor) RedactRequest(req Request) (Request, int) {
+	if r == nil {
+		return req, 0
+	}
+
+	var total, n int
+	req.System, total = r.Redact(req.System)
+	mm := make([]Message, len(req.Messages))
+	for i, m := range req.Messages {
+		m.Content, n = r.Redact(m.Content)
+		total += n
+		if len(m.Blocks) > 0 {
+			bb := make([]ContentBlock, len(m.Blocks))
+			for j, b := range m.Blocks {
+				b.Text, n = r.Redact(b.Text)
+				total += n
+				b.Content, n = r.Redact(b.Content)
+				total += n
+				bb[j] = b
+			}
+			m.Blocks = bb
+		}
+		mm[i] = m
+	}
+	req.Messages = mm
+
+	return req, total
+}
+
+func mask(s string, rx *regexp.Regexp, group int) (string, int) {
+	ii := rx.FindAllStringSubmatchIndex(s, -1)
+	if len(ii) == 0 {
+		return s, 0
+	}
+
+	var (
+		sb         strings.Builder
+		last, hits int
+	)
+	for _, m := range ii {
+		start, end := m[2*group], m[2*group+1]
+		if start < 0 || !maskable(s[start:end]) {
+			continue
+		}
+		sb.WriteString(s[last:start])
+		sb.WriteString(Redacted)
+		last = end
+		hits++
+	}
+	sb.WriteString(s[last:])
+
+	return sb.String(), hits
+}
+
+// maskable skips values that carry no secret, such as flags, empty strings
+// or describe placeholders, so the model keeps seeing them.
+func maskable(v string) bool {
+	v = strings.Trim(v, `"'`)
+	if strings.HasPrefix(v, "<") {
+		return false
+	}
+	switch strings.ToLower(v) {
+	case "", "true", "false", "null", strings.ToLower(Redacted):
+		return false
+	default:
+		return true
+	}
+}
+
+// redactSecretData masks Secret data values in YAML or JSON manifests.
+func redactSecretData(s string) (string, int) {
+	if !secretKindRX.MatchString(s) {
+		return s, 0
+	}
+
+	var hits int
+	s = dataJSONRX.ReplaceAllStringFunc(s, func(d string) string {
+		d, n := mask(d, dataValueRX, 1)
+		hits += n
+		return d
+	})
+
+	ll := strings.Split(s, "\n")
+	indent := -1
+	for i, l := range ll {
+		if m := secretDataRX.FindStringSubmatch(l); m != nil {
+			indent = len(m[1])
+			continue
+		}
+		if indent < 0 || strings.TrimSpace(l) == "" {
+			continue
+		}
+		if len(l)-len(strings.TrimLeft(l, " ")) <= indent {
+			indent = -1
+			continue
+		}
+		if k, v, ok := strings.Cut(l, ":"); ok && maskable(strings.TrimSpace(v)) {
+			ll[i] = k + ": " + Redacted
+			hits++
+		}
+	}
+
+	return strings.Join(ll, "\n"), hits
+}
@@ -0,0 +1,106 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of K9s
+
+package ai
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+)
+
+const (
+	retryInitialInterval = 1 * time.Second
+	retryMaxInterval     = 20 * time.Second
+
+	// maxRetryAfter caps how long a provider may ask us to wait.
+	maxRetryAfter = time.Minute
+)
+
+// RetryFunc is notified before a failed request is tried again.
+type RetryFunc func(attempt, maxRetries int, wait time.Duration, err error)
+
+// RetryPolicy tracks how throttled or failed requests are retried.
+type RetryPolicy struct {
+	// MaxRetries tracks the number of retries. Zero disables retries.
+	MaxRetries int
+
+	// InitialInterval tracks the first backoff delay.
+	InitialInterval time.Duration
+
+	// MaxInterval caps the backoff delay.
+	MaxInterval time.Duration
+}
+
+// NewRetryPolicy returns a policy retrying up to n times.
+func NewRetryPolicy(n int) RetryPolicy {
+	return RetryPolicy{
+		MaxRetries:      n,
+		InitialInterval: retryInitialInterval,
+		MaxInterval:     retryMaxInterval,
+	}
+}
+
+func (p RetryPolicy) backOff(ctx context.Context) backoff.BackOffContext {
+	bf := backoff.NewExponentialBackOff()
+	bf.InitialInterval = p.InitialInterval
+	bf.MaxInterval = p.MaxInterval
+	bf.MaxElapsedTime = 0
+	bf.Reset()
+
+	return backoff.WithContext(backoff.WithMaxRetries(bf, uint64(max(p.MaxRetries, 0))), ctx)
+}
+
+// SetRetryPolicy sets how failed requests are retried.
+func (c *Client) SetRetryPolicy(p RetryPolicy) {
+	c.retry = p
+}
+
+// SetRetryFunc sets a callback notified before each retry.
+func (c *Client) SetRetryFunc(fn RetryFunc) {
+	c.onRetry = fn
+}
+
+// withRetry issues a request, retrying on throttling or transient failures.
+// Requests that already produced content are never retried as the caller
+// has seen part of the answer. A provider retry-after hint takes precedence
+// over the backoff delay.
+func (c *Client) withRetry(ctx context.Context, call func() (*Response, error)) (*Response, error) {
+	bf := c.retry.backOff(ctx)
+	for attempt := 1; ; attempt++ {
+		resp, err := call()
+		c.record(resp)
+		if err == nil || !IsRetryable(err) || (resp != nil && len(resp.Content) > 0) {
+			return resp, err
+		}
+		wait := bf.NextBackOff()
+		if wait == backoff.Stop {
+			return resp, err
+		}
+		if ra := retryAfter(err); ra > 0 {
+			wait = min(ra, maxRetryAfter)
+		}
+		if c.onRetry != nil {
+			c.onRetry(attempt, c.retry.MaxRetries, wait, err)
+		}
+
+		t := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return resp, ctx.Err()
+		case <-t.C:
+		}
+	}
+}
+
+func retryAfter(err error) time.Duration {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.RetryAfter
+	}
+
+	return 0
+}
@@ -0,0 +1,161 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of K9s
+
+package ai_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/quentincherifi/c9s/internal/ai"
+	"github.com/quentincherifi/c9s/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDescribe(t *testing.T) {
+	uu := map[string]struct {
+		err       error
+		e         string
+		retryable bool
+	}{
+		"auth": {
+			err: &ai.APIError{StatusCode: http.StatusUnauthorized, Type: "authentication_error", Message: "invalid x-api-key"},
+			e:   "Authentication failed. Check your API key or set it using `:claude set-key`.",
+		},
+		"throttled": {
+			err:       &ai.APIError{StatusCode: http.StatusTooManyRequests, Type: "rate_limit_error", Message: "slow down"},
+			e:         "Rate limited by the AI provider. Wait a moment and try again.",
+			retryable: true,
+		},
+		"overloaded": {
+			err:       &ai.APIError{Type: "overloaded_error", Message: "Overloaded"},
+			e:         "The AI provider is overloaded. Wait a moment and try again.",
+			retryable: true,
+		},
+		"invalid": {
+			err: &ai.APIError{StatusCode: http.StatusBadRequest, Message: "bad json"},
+			e:   "Invalid request: bad json",
+		},
+		"gateway": {
+			err:       &ai.APIError{StatusCode: http.StatusBadGateway, Message: "bad gateway"},
+			e:         "The AI provider failed: bad gateway",
+			retryable: true,
+		},
+		"unreachable": {
+			err:       fmt.Errorf("%w: %w", ai.ErrUnreachable, errors.New("connection refused")),
+			e:         "Unable to reach the AI provider. Check your network or the configured base URL (connection refused).",
+			retryable: true,
+		},
+		"canceled": {
+			err: context.Canceled,
+			e:   "context canceled",
+		},
+	}
+
+	for k := range uu {
+		u := uu[k]
+		t.Run(k, func(t *testing.T) {
+			assert.Equal(t, u.e, ai.Describe(u.err))
+			assert.Equal(t, u.retryable, ai.IsRetryable(u.err))
+		})
+	}
+}
+
+func TestClientRetry(t *testing.T) {
+	uu := map[string]struct {
+		statuses []int
+		retries  int
+		calls    int32
+		err      string
+	}{
+		"recovers": {
+			statuses: []int{http.StatusTooManyRequests, 529, http.StatusOK},
+			retries:  3,
+			calls:    3,
+		},
+		"exhausted": {
+			statuses: []int{http.StatusTooManyRequests, http.StatusTooManyRequests, http.StatusTooManyRequests},
+			retries:  1,
+			calls:    2,
+			err:      "API error: rate_limit_error - slow down",
+		},
+		"permanent": {
+			statuses: []int{http.StatusUnauthorized, http.StatusOK},
+			retries:  3,
+			calls:    1,
+			err:      "API error: authentication_error - invalid x-api-key",
+		},
+	}
+
+	for k := range uu {
+		u := uu[k]
+		t.Run(k, func(t *testing.T) {
+			var calls atomic.Int32
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_, _ = io.Copy(io.Discard, r.Body)
+				status := u.statuses[calls.Add(1)-1]
+				if status == http.StatusOK {
+					_, _ = w.Write([]byte(`{"content":[{"type":"text","text":"hi"}],"stop_reason":"end_turn"}`))
+					return
+				}
+				w.Header().Set("Retry-After", "0")
+				w.WriteHeader(status)
+				if status == http.StatusUnauthorized {
+					_, _ = w.Write([]byte(`{"type":"error","error":{"type":"authentication_error","message":"invalid x-api-key"}}`))
+					return
+				}
+				_, _ = w.Write([]byte(`{"type":"error","error":{"type":"rate_limit_error","message":"slow down"}}`))
+			}))
+			defer srv.Close()
+
+			c, err := ai.NewConfigClient(&config.AI{BaseURL: srv.URL}, "key")
+			require.NoError(t, err)
+			c.SetRetryPolicy(ai.RetryPolicy{MaxRetries: u.retries, InitialInterval: time.Millisecond, MaxInterval: time.Millisecond})
+			var notified int
+			c.SetRetryFunc(func(attempt, maxRetries int, _ time.Duration, _ error) {
+				notified++
+				assert.Equal(t, notified, attempt)
+				assert.Equal(t, u.retries, maxRetries)
+			})
+
+			res, err := c.Send(context.Background(), "", []ai.Message{{Role: "user", Content: "hello"}})
+			assert.Equal(t, u.calls, calls.Load())
+			assert.Equal(t, int(u.calls)-1, notified)
+			if u.err != "" {
+				assert.EqualError(t, err, u.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "hi", res.GetText())
+		})
+	}
+}
+
+func TestClientRetryCancel(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.Copy(io.Discard, r.Body)
+		w.Header().Set("Retry-After", "30")
+		w.WriteHeader(529)
+		_, _ = w.Write([]byte(`{"type":"error","error":{"type":"overloaded_error","message":"Overloaded"}}`))
+	}))
+	defer srv.Close()
+
+	c, err := ai.NewConfigClient(&config.AI{BaseURL: srv.URL}, "key")
+	require.NoError(t, err)
+	ctx, cancel := context.WithCancel(context.Background())
+	c.SetRetryFunc(func(_, _ int, wait time.Duration, _ error) {
+		assert.Equal(t, 30*time.Second, wait)
+		cancel()
+	})
+
+	_, err = c.Send(ctx, "", []ai.Message{{Role: "user", Content: "hello"}})
+	assert.ErrorIs(t, err, context.Canceled)
+}
@@ -0,0 +1,230 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of K9s
+
+package ai
+
+import (
+	"errors"
+	"io/fs"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+const (
+	// BM25 term frequency saturation and length normalization.
+	bm25K1 = 1.2
+	bm25B  = 0.75
+
+	runbookExt = ".md"
+)
+
+// runbookStopWords tracks words too common to rank runbooks.
+var runbookStopWords = map[string]struct{}{
+	"a": {}, "an": {}, "and": {}, "are": {}, "as": {}, "at": {}, "be": {}, "by": {},
+	"can": {}, "do": {}, "does": {}, "for": {}, "from": {}, "how": {}, "i": {}, "if": {},
+	"in": {}, "is": {}, "it": {}, "my": {}, "of": {}, "on": {}, "or": {}, "our": {},
+	"so": {}, "that": {}, "the": {}, "this": {}, "to": {}, "we": {}, "what": {},
+	"when": {}, "why": {}, "with": {}, "you": {},
+}
+
+// Excerpt represents a runbook section matching a question.
+type Excerpt struct {
+	File    string
+	Heading string
+	Text    string
+	Score   float64
+}
+
+type runbookSection struct {
+	file, heading, text string
+	terms               map[string]int
+	size                int
+}
+
+// RunbookIndex is an offline BM25 index over Markdown runbook sections.
+type RunbookIndex struct {
+	sections []runbookSection
+	df       map[string]int
+	total    int
+}
+
+// NewRunbookIndex returns a new empty index.
+func NewRunbookIndex() *RunbookIndex {
+	return &RunbookIndex{df: make(map[string]int)}
+}
+
+// LoadRunbooks indexes the Markdown files found in the given directories.
+// Files are cited relative to their directory. Unreadable files or
+// directories are reported but do not prevent others from being indexed.
+func LoadRunbooks(dirs []string) (*RunbookIndex, error) {
+	var (
+		idx  = NewRunbookIndex()
+		errs error
+	)
+	for _, dir := range dirs {
+		err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				errs = errors.Join(errs, err)
+				return nil
+			}
+			if d.IsDir() || !strings.EqualFold(filepath.Ext(path), runbookExt) {
+				return nil
+			}
+			bb, err := os.ReadFile(path)
+			if err != nil {
+				errs = errors.Join(errs, err)
+				return nil
+			}
+			rel, err := filepath.Rel(dir, path)
+			if err != nil {
+				rel = path
+			}
+			idx.Add(filepath.ToSlash(rel), string(bb))
+			return nil
+		})
+		errs = errors.Join(errs, err)
+	}
+
+	return idx, errs
+}
+
+// Len returns the number of indexed sections.
+func (r *RunbookIndex) Len() int {
+	if r == nil {
+		return 0
+	}
+
+	return len(r.sections)
+}
+
+// Add indexes a runbook, splitting it into sections at each heading.
+func (r *RunbookIndex) Add(file, md string) {
+	for _, s := range splitSections(md) {
+		tt := tokenize(s.heading + " " + s.text)
+		if len(tt) == 0 {
+			continue
+		}
+		s.file, s.terms, s.size = file, make(map[string]int, len(tt)), len(tt)
+		for _, t := range tt {
+			s.terms[t]++
+		}
+		for t := range s.terms {
+			r.df[t]++
+		}
+		r.total += s.size
+		r.sections = append(r.sections, s)
+	}
+}
+
+// Search returns up to n sections best matching the query, capped to the
+// given byte budget. Sections not sharing any term with the query are
+// never returned.
+func (r *RunbookIndex) Search(query string, n, size int) []Excerpt {
+	if r.Len() == 0 || n <= 0 {
+		return nil
+	}
+	qq := tokenize(query)
+	if len(qq) == 0 {
+		return nil
+	}
+
+	var (
+		count = float64(len(r.sections))
+		avg   = float64(r.total) / count
+		ee    = make([]Excerpt, 0, len(r.sections))
+	)
+	for _, s := range r.sections {
+		var score float64
+		for _, q := range qq {
+			tf := float64(s.terms[q])
+			if tf == 0 {
+				continue
+			}
+			df := float64(r.df[q])
+			idf := math.Log(1 + (count-df+0.5)/(df+0.5))
+			score += idf * tf * (bm25K1 + 1) / (tf + bm25K1*(1-bm25B+bm25B*float64(s.size)/avg))
+		}
+		if score > 0 {
+			ee = append(ee, Excerpt{File: s.file, Heading: s.heading, Text: s.text, Score: score})
+		}
+	}
+	sort.SliceStable(ee, func(i, j int) bool { return ee[i].Score > ee[j].Score })
+
+	out := make([]Excerpt, 0, n)
+	for _, e := range ee {
+		if len(out) == n || size <= 0 {
+			break
+		}
+		e.Text = TrimText(e.Text, size)
+		size -= len(e.Text)
+		out = append(out, e)
+	}
+
+	return out
+}
+
+// ExcerptFiles returns the distinct files excerpts come from.
+func ExcerptFiles(ee []Excerpt) []string {
+	ff := make([]string, 0, len(ee))
+	for _, e := range ee {
+		if !slices.Contains(ff, e.File) {
+			ff = append(ff, e.File)
+		}
+	}
+
+	return ff
+}
+
+// splitSections splits Markdown at its headings, skipping fenced code.
+func splitSections(md string) []runbookSection {
+	var (
+		ss      []runbookSection
+		cur     runbookSection
+		lines   []string
+		inFence bool
+	)
+	flush := func() {
+		if text := strings.TrimSpace(strings.Join(lines, "\n")); text != "" || cur.heading != "" {
+			cur.text = text
+			ss = append(ss, cur)
+		}
+		lines = nil
+	}
+	for _, l := range strings.Split(md, "\n") {
+		if t := strings.TrimSpace(l); strings.HasPrefix(t, "```") || strings.HasPrefix(t, "~~~") {
+			inFence = !inFence
+		}
+		if !inFence && strings.HasPrefix(l, "#") {
+			if h := strings.TrimSpace(strings.TrimLeft(l, "#")); h != "" {
+				flush()
+				cur = runbookSection{heading: h}
+				continue
+			}
+		}
+		lines = append(lines, l)
+	}
+	flush()
+
+	return ss
+}
+
+// tokenize splits text into lower case terms, common words dropped.
+func tokenize(s string) []string {
+	ff := strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	tt := ff[:0]
+	for _, f := range ff {
+		if _, ok := runbookStopWords[f]; ok || len(f) < 2 {
+			continue
+		}
+		tt = append(tt, f)
+	}
+
+	return tt
+}
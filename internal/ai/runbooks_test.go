@@ -0,0 +1,126 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of K9s
+
+package ai_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/quentincherifi/c9s/internal/ai"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	ingressRunbook = `# Ingress
+
+## 502 Bad Gateway
+Check the backend service endpoints. Our ingress controller returns a 502
+when no endpoint is ready.
+
+## TLS
+Certificates are issued by cert-manager using the letsencrypt issuer.
+`
+	dbRunbook = `# Database
+
+## Connection refused
+Pods must reach postgres through the pgbouncer service on port 6432.
+
+` + "```sh\n# not a heading\nkubectl -n db get svc pgbouncer\n```\n"
+)
+
+func TestRunbookSearch(t *testing.T) {
+	idx := ai.NewRunbookIndex()
+	idx.Add("ingress.md", ingressRunbook)
+	idx.Add("db/postgres.md", dbRunbook)
+
+	uu := map[string]struct {
+		q     string
+		n     int
+		files []string
+		heads []string
+	}{
+		"ingress": {
+			q:     "Why does the ingress return a 502?",
+			n:     1,
+			files: []string{"ingress.md"},
+			heads: []string{"502 Bad Gateway"},
+		},
+		"db": {
+			q:     "connection refused to postgres",
+			n:     3,
+			files: []string{"db/postgres.md"},
+			heads: []string{"Connection refused"},
+		},
+		"fenced": {
+			q:     "pgbouncer kubectl",
+			n:     3,
+			files: []string{"db/postgres.md"},
+			heads: []string{"Connection refused"},
+		},
+		"no-match": {
+			q: "why is the sky blue",
+			n: 3,
+		},
+		"stop-words": {
+			q: "what is this",
+			n: 3,
+		},
+	}
+
+	for k := range uu {
+		u := uu[k]
+		t.Run(k, func(t *testing.T) {
+			ee := idx.Search(u.q, u.n, 1024)
+			heads := make([]string, 0, len(ee))
+			for _, e := range ee {
+				heads = append(heads, e.Heading)
+			}
+			assert.Equal(t, u.files, nilIfEmpty(ai.ExcerptFiles(ee)))
+			assert.Equal(t, u.heads, nilIfEmpty(heads))
+		})
+	}
+}
+
+func TestRunbookSearchBudget(t *testing.T) {
+	idx := ai.NewRunbookIndex()
+	idx.Add("ingress.md", ingressRunbook)
+
+	ee := idx.Search("ingress 502 endpoint", 3, 20)
+	require.Len(t, ee, 1)
+	assert.Equal(t, "Check the backend se\n... [truncated]", ee[0].Text)
+}
+
+func TestLoadRunbooks(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "db"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "ingress.md"), []byte(ingressRunbook), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "db", "postgres.md"), []byte(dbRunbook), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("ingress 502"), 0o600))
+
+	idx, err := ai.LoadRunbooks([]string{dir, filepath.Join(dir, "missing")})
+	require.Error(t, err)
+	assert.Equal(t, 5, idx.Len())
+
+	ee := idx.Search("pgbouncer", 3, 1024)
+	assert.Equal(t, []string{"db/postgres.md"}, ai.ExcerptFiles(ee))
+}
+
+func TestBuildSystemPromptRunbooks(t *testing.T) {
+	p, err := ai.BuildSystemPrompt(&ai.K8sContext{
+		Runbooks: []ai.Excerpt{{File: "ingress.md", Heading: "TLS", Text: "Use cert-manager."}},
+	})
+	require.NoError(t, err)
+	assert.Contains(t, p, "--- Runbook: ingress.md (TLS)\nUse cert-manager.\n---")
+	assert.Contains(t, p, "[runbook: <file>]")
+}
+
+func nilIfEmpty(ss []string) []string {
+	if len(ss) == 0 {
+		return nil
+	}
+
+	return ss
+}
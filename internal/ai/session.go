@@ -0,0 +1,195 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of K9s
+
+package ai
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	// SessionExt tracks session file extension.
+	SessionExt = ".json"
+
+	// SessionDirMod tracks session directory permissions. Sessions hold
+	// unredacted tool output and are private to the user.
+	SessionDirMod = 0o700
+
+	sessionIDFmt   = "20060102-150405.000"
+	sessionFileMod = 0o600
+)
+
+// Session represents a persisted conversation.
+type Session struct {
+	ID        string    `json:"id"`
+	Title     string    `json:"title,omitempty"`
+	Cluster   string    `json:"cluster,omitempty"`
+	Context   string    `json:"context,omitempty"`
+	GVR       string    `json:"gvr,omitempty"`
+	Path      string    `json:"path,omitempty"`
+	Question  string    `json:"question,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+	Messages  []Message `json:"messages"`
+
+	// Usage tracks token usage per message index.
+	Usage []MessageUsage `json:"usage,omitempty"`
+
+	// Pinned tracks pinned message indexes.
+	Pinned []int `json:"pinned,omitempty"`
+
+	// Compaction tracks the summarized leading messages if any.
+	Compaction *Compaction `json:"compaction,omitempty"`
+}
+
+// MessageUsage tracks the tokens consumed producing a message.
+type MessageUsage struct {
+	Index int `json:"index"`
+	Usage
+}
+
+// NewSession returns a new conversation.
+func NewSession(cluster, context string) *Session {
+	now := time.Now()
+
+	return &Session{
+		ID:        strings.ReplaceAll(now.UTC().Format(sessionIDFmt), ".", "-"),
+		Cluster:   cluster,
+		Context:   context,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
+
+// LoadSession loads a conversation from disk.
+func LoadSession(path string) (*Session, error) {
+	bb, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var s Session
+	if err := json.Unmarshal(bb, &s); err != nil {
+		return nil, fmt.Errorf("session load failed %q: %w", path, err)
+	}
+	for _, u := range s.Usage {
+		if u.Index >= 0 && u.Index < len(s.Messages) {
+			s.Messages[u.Index].Usage = &u.Usage
+		}
+	}
+	for _, i := range s.Pinned {
+		if i >= 0 && i < len(s.Messages) {
+			s.Messages[i].Pinned = true
+		}
+	}
+
+	return &s, nil
+}
+
+// SessionPath returns the session file location in the given dir.
+func (s *Session) SessionPath(dir string) string {
+	return filepath.Join(dir, s.ID+SessionExt)
+}
+
+// SetMessages updates the conversation messages. Notices are not persisted.
+func (s *Session) SetMessages(mm []Message) {
+	mm = WithoutNotices(mm)
+	s.Messages, s.UpdatedAt = mm, time.Now()
+	s.Usage, s.Pinned = s.Usage[:0], s.Pinned[:0]
+	for i, m := range mm {
+		if m.Usage != nil {
+			s.Usage = append(s.Usage, MessageUsage{Index: i, Usage: *m.Usage})
+		}
+		if m.Pinned {
+			s.Pinned = append(s.Pinned, i)
+		}
+	}
+	if s.Question != "" {
+		return
+	}
+	for _, m := range mm {
+		if m.Role == "user" && m.Content != "" {
+			s.Question = m.Content
+			return
+		}
+	}
+}
+
+// Save persists the conversation in the given dir.
+func (s *Session) Save(dir string) error {
+	if err := os.MkdirAll(dir, SessionDirMod); err != nil {
+		return err
+	}
+	bb, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(s.SessionPath(dir), bb, sessionFileMod)
+}
+
+// Tokens returns the tokens consumed by the conversation.
+func (s *Session) Tokens() Usage {
+	return TotalUsage(s.Messages)
+}
+
+// Name returns the session display name.
+func (s *Session) Name() string {
+	if s.Title != "" {
+		return s.Title
+	}
+
+	return s.ID
+}
+
+// Markdown renders the conversation as a markdown transcript.
+func (s *Session) Markdown() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "# %s\n\n", s.Name())
+	fmt.Fprintf(&sb, "- Cluster: %s\n", s.Cluster)
+	fmt.Fprintf(&sb, "- Context: %s\n", s.Context)
+	if s.Path != "" {
+		fmt.Fprintf(&sb, "- Resource: %s %s\n", s.GVR, s.Path)
+	}
+	fmt.Fprintf(&sb, "- Started: %s\n", s.CreatedAt.Format(time.RFC3339))
+	fmt.Fprintf(&sb, "- Updated: %s\n", s.UpdatedAt.Format(time.RFC3339))
+
+	for _, m := range s.Messages {
+		if m.Notice {
+			continue
+		}
+		if len(m.Blocks) == 0 {
+			fmt.Fprintf(&sb, "\n## %s\n\n%s\n", roleTitle(m.Role), m.Content)
+			continue
+		}
+		if text := m.Text(); text != "" {
+			fmt.Fprintf(&sb, "\n## %s\n\n%s\n", roleTitle(m.Role), text)
+		}
+		for _, b := range m.Blocks {
+			switch b.Type {
+			case BlockToolUse:
+				fmt.Fprintf(&sb, "\n> Tool `%s` %s\n", b.Name, string(b.Input))
+			case BlockToolResult:
+				if b.IsError {
+					fmt.Fprintf(&sb, "> Failed: %s\n", b.Content)
+				} else {
+					fmt.Fprintf(&sb, "> Read %d bytes\n", len(b.Content))
+				}
+			}
+		}
+	}
+
+	return sb.String()
+}
+
+func roleTitle(r string) string {
+	if r == "user" {
+		return "You"
+	}
+
+	return "Claude"
+}
@@ -0,0 +1,77 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of K9s
+
+package ai_test
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/quentincherifi/c9s/internal/ai"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSessionSaveLoad(t *testing.T) {
+	dir := t.TempDir()
+
+	s := ai.NewSession("c1", "ctx1")
+	s.GVR, s.Path = "v1/pods", "default/p1"
+	s.SetMessages([]ai.Message{
+		{Role: "user", Content: "why is p1 failing?", Pinned: true},
+		{Role: "assistant", Blocks: []ai.ContentBlock{
+			{Type: ai.BlockToolUse, ID: "t1", Name: "get_logs", Input: json.RawMessage(`{"name":"p1"}`)},
+		}},
+		{Role: "user", Blocks: []ai.ContentBlock{
+			{Type: ai.BlockToolResult, ToolUseID: "t1", Content: "boom"},
+		}},
+		{Role: "assistant", Content: "It panics on boot.", Usage: &ai.Usage{InputTokens: 120, OutputTokens: 30}},
+	})
+	require.NoError(t, s.Save(dir))
+
+	fi, err := os.Stat(s.SessionPath(dir))
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), fi.Mode().Perm())
+
+	l, err := ai.LoadSession(s.SessionPath(dir))
+	require.NoError(t, err)
+	assert.Equal(t, s.ID, l.ID)
+	assert.Equal(t, "why is p1 failing?", l.Question)
+	assert.Equal(t, "default/p1", l.Path)
+	require.Len(t, l.Messages, 4)
+	assert.Equal(t, "get_logs", l.Messages[1].Blocks[0].Name)
+	assert.Equal(t, "It panics on boot.", l.Messages[3].Content)
+	assert.Nil(t, l.Messages[0].Usage)
+	assert.Equal(t, &ai.Usage{InputTokens: 120, OutputTokens: 30}, l.Messages[3].Usage)
+	assert.Equal(t, 150, l.Tokens().Total())
+	assert.True(t, l.Messages[0].Pinned)
+	assert.False(t, l.Messages[3].Pinned)
+}
+
+func TestSessionMarkdown(t *testing.T) {
+	s := ai.NewSession("c1", "ctx1")
+	s.Title = "p1 crash"
+	s.SetMessages([]ai.Message{
+		{Role: "user", Content: "why?"},
+		{Role: "assistant", Blocks: []ai.ContentBlock{
+			{Type: ai.BlockText, Text: "Checking"},
+			{Type: ai.BlockToolUse, Name: "get_logs", Input: json.RawMessage(`{}`)},
+		}},
+		{Role: "user", Blocks: []ai.ContentBlock{
+			{Type: ai.BlockToolResult, Content: "denied", IsError: true},
+		}},
+		{Role: "assistant", Content: "Done"},
+		{Role: "assistant", Content: "Error: [red]boom", Notice: true},
+	})
+
+	md := s.Markdown()
+	assert.Len(t, s.Messages, 4)
+	assert.NotContains(t, md, "boom")
+	assert.Contains(t, md, "# p1 crash\n")
+	assert.Contains(t, md, "## You\n\nwhy?\n")
+	assert.Contains(t, md, "## Claude\n\nChecking\n")
+	assert.Contains(t, md, "> Tool `get_logs` {}")
+	assert.Contains(t, md, "> Failed: denied")
+	assert.Contains(t, md, "## Claude\n\nDone\n")
+}
@@ -0,0 +1,149 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of K9s
+
+package ai
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+const (
+	eventMessageStart      = "message_start"
+	eventContentBlockStart = "content_block_start"
+	eventContentBlockDelta = "content_block_delta"
+	eventMessageDelta      = "message_delta"
+	eventMessageStop       = "message_stop"
+	eventError             = "error"
+
+	deltaText      = "text_delta"
+	deltaInputJSON = "input_json_delta"
+
+	maxEventSize = 1024 * 1024
+)
+
+// StreamFunc is called with each text fragment as it arrives.
+type StreamFunc func(text string)
+
+// StreamEvent represents a server-sent event emitted by the messages API.
+type StreamEvent struct {
+	Type         string        `json:"type"`
+	Index        int           `json:"index"`
+	Message      *Response     `json:"message,omitempty"`
+	ContentBlock *ContentBlock `json:"content_block,omitempty"`
+	Delta        struct {
+		Type        string `json:"type"`
+		Text        string `json:"text"`
+		PartialJSON string `json:"partial_json"`
+		StopReason  string `json:"stop_reason"`
+	} `json:"delta"`
+	Usage *Usage `json:"usage,omitempty"`
+	Error *struct {
+		Type    string `json:"type"`
+		Message string `json:"message"`
+	} `json:"error,omitempty"`
+}
+
+// Stream sends a message to Claude in streaming mode. Text deltas are handed
+// to fn as they arrive and the assembled response is returned once the
+// stream completes. Cancelling ctx aborts the stream.
+func (c *Client) Stream(ctx context.Context, system string, messages []Message, fn StreamFunc) (*Response, error) {
+	return c.stream(ctx, c.request(system, messages, nil), fn)
+}
+
+func (c *Client) stream(ctx context.Context, req Request, fn StreamFunc) (*Response, error) {
+	if err := c.checkBudget(); err != nil {
+		return nil, err
+	}
+	req, _ = c.redactor.RedactRequest(req)
+
+	return c.withRetry(ctx, func() (*Response, error) {
+		return c.provider.Stream(ctx, req, fn)
+	})
+}
+
+func readStream(r io.Reader, fn StreamFunc) (*Response, error) {
+	var (
+		res     Response
+		scanner = bufio.NewScanner(r)
+	)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxEventSize)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if !strings.HasPrefix(line, "data:") {
+			continue
+		}
+		var evt StreamEvent
+		if err := json.Unmarshal([]byte(strings.TrimSpace(line[len("data:"):])), &evt); err != nil {
+			return &res, fmt.Errorf("failed to parse stream event: %w", err)
+		}
+		done, err := res.apply(&evt, fn)
+		if err != nil {
+			return &res, err
+		}
+		if done {
+			return &res, nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return &res, fmt.Errorf("failed to read stream: %w", err)
+	}
+
+	return &res, errors.New("stream closed before message completed")
+}
+
+func (r *Response) apply(evt *StreamEvent, fn StreamFunc) (bool, error) {
+	switch evt.Type {
+	case eventMessageStart:
+		if evt.Message != nil {
+			*r = *evt.Message
+		}
+	case eventContentBlockStart:
+		if evt.ContentBlock != nil {
+			b := *evt.ContentBlock
+			if b.Type == BlockToolUse {
+				b.Input = nil
+			}
+			r.Content = append(r.Content, b)
+		}
+	case eventContentBlockDelta:
+		for len(r.Content) <= evt.Index {
+			r.Content = append(r.Content, ContentBlock{Type: BlockText})
+		}
+		switch evt.Delta.Type {
+		case deltaText:
+			r.Content[evt.Index].Text += evt.Delta.Text
+			if fn != nil {
+				fn(evt.Delta.Text)
+			}
+		case deltaInputJSON:
+			r.Content[evt.Index].Input = append(r.Content[evt.Index].Input, evt.Delta.PartialJSON...)
+		}
+	case eventMessageDelta:
+		if evt.Delta.StopReason != "" {
+			r.StopReason = evt.Delta.StopReason
+		}
+		if evt.Usage != nil {
+			r.Usage.OutputTokens = evt.Usage.OutputTokens
+		}
+	case eventMessageStop:
+		for i := range r.Content {
+			if r.Content[i].Type == BlockToolUse && len(r.Content[i].Input) == 0 {
+				r.Content[i].Input = json.RawMessage("{}")
+			}
+		}
+		return true, nil
+	case eventError:
+		if evt.Error != nil {
+			return false, &APIError{Type: evt.Error.Type, Message: evt.Error.Message}
+		}
+		return false, errors.New("API error: unknown stream error")
+	}
+
+	return false, nil
+}
@@ -0,0 +1,123 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of K9s
+
+package ai
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const sseOK = `event: message_start
+data: {"type":"message_start","message":{"id":"msg_1","type":"message","role":"assistant","content":[],"model":"m","usage":{"input_tokens":12,"output_tokens":1}}}
+
+event: content_block_start
+data: {"type":"content_block_start","index":0,"content_block":{"type":"text","text":""}}
+
+event: ping
+data: {"type":"ping"}
+
+event: content_block_delta
+data: {"type":"content_block_delta","index":0,"delta":{"type":"text_delta","text":"Hello"}}
+
+event: content_block_delta
+data: {"type":"content_block_delta","index":0,"delta":{"type":"text_delta","text":" world"}}
+
+event: content_block_stop
+data: {"type":"content_block_stop","index":0}
+
+event: message_delta
+data: {"type":"message_delta","delta":{"stop_reason":"end_turn"},"usage":{"output_tokens":7}}
+
+event: message_stop
+data: {"type":"message_stop"}
+`
+
+const sseTool = `data: {"type":"message_start","message":{"id":"msg_2","type":"message","role":"assistant","content":[],"model":"m","usage":{"input_tokens":3}}}
+
+data: {"type":"content_block_start","index":0,"content_block":{"type":"text","text":""}}
+
+data: {"type":"content_block_delta","index":0,"delta":{"type":"text_delta","text":"Checking"}}
+
+data: {"type":"content_block_start","index":1,"content_block":{"type":"tool_use","id":"toolu_1","name":"get_logs","input":{}}}
+
+data: {"type":"content_block_delta","index":1,"delta":{"type":"input_json_delta","partial_json":"{\"name\":"}}
+
+data: {"type":"content_block_delta","index":1,"delta":{"type":"input_json_delta","partial_json":"\"p1\"}"}}
+
+data: {"type":"message_delta","delta":{"stop_reason":"tool_use"},"usage":{"output_tokens":20}}
+
+data: {"type":"message_stop"}
+`
+
+func TestReadStreamToolUse(t *testing.T) {
+	res, err := readStream(strings.NewReader(sseTool), nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, "tool_use", res.StopReason)
+	assert.Equal(t, "Checking", res.GetText())
+	uu := res.ToolUses()
+	require.Len(t, uu, 1)
+	assert.Equal(t, "toolu_1", uu[0].ID)
+	assert.Equal(t, "get_logs", uu[0].Name)
+	assert.JSONEq(t, `{"name":"p1"}`, string(uu[0].Input))
+}
+
+func TestReadStream(t *testing.T) {
+	uu := map[string]struct {
+		in     string
+		deltas []string
+		text   string
+		stop   string
+		usage  Usage
+		err    string
+	}{
+		"happy": {
+			in:     sseOK,
+			deltas: []string{"Hello", " world"},
+			text:   "Hello world",
+			stop:   "end_turn",
+			usage:  Usage{InputTokens: 12, OutputTokens: 7},
+		},
+		"api-error": {
+			in: `event: error
+data: {"type":"error","error":{"type":"overloaded_error","message":"Overloaded"}}
+`,
+			err: "API error: overloaded_error - Overloaded",
+		},
+		"truncated": {
+			in: `data: {"type":"content_block_delta","index":0,"delta":{"type":"text_delta","text":"Hel"}}
+`,
+			deltas: []string{"Hel"},
+			text:   "Hel",
+			err:    "stream closed before message completed",
+		},
+		"bad-json": {
+			in:  "data: {nope\n",
+			err: "failed to parse stream event",
+		},
+	}
+
+	for k := range uu {
+		u := uu[k]
+		t.Run(k, func(t *testing.T) {
+			var deltas []string
+			res, err := readStream(strings.NewReader(u.in), func(s string) {
+				deltas = append(deltas, s)
+			})
+			if u.err != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), u.err)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, u.stop, res.StopReason)
+				assert.Equal(t, u.usage, res.Usage)
+			}
+			assert.Equal(t, u.deltas, deltas)
+			assert.Equal(t, u.text, res.GetText())
+		})
+	}
+}
@@ -0,0 +1,160 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of K9s
+
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+)
+
+const (
+	stopToolUse   = "tool_use"
+	maxToolRounds = 10
+)
+
+// ToolHandler executes a tool invocation and returns its textual result.
+type ToolHandler func(ctx context.Context, input json.RawMessage) (string, error)
+
+// Tool describes a tool the model may call.
+type Tool struct {
+	Name        string          `json:"name"`
+	Description string          `json:"description"`
+	InputSchema json.RawMessage `json:"input_schema"`
+}
+
+// ToolCall records a tool invocation and its outcome.
+type ToolCall struct {
+	Name   string
+	Input  string
+	Result string
+	Err    error
+}
+
+// ToolFunc is notified of each tool invocation once it completes.
+type ToolFunc func(ToolCall)
+
+// Toolbox tracks tools available to the model.
+type Toolbox struct {
+	tools    []Tool
+	handlers map[string]ToolHandler
+	mx       sync.RWMutex
+}
+
+// NewToolbox returns a new instance.
+func NewToolbox() *Toolbox {
+	return &Toolbox{
+		handlers: make(map[string]ToolHandler),
+	}
+}
+
+// Register adds a tool to the toolbox.
+func (t *Toolbox) Register(tool Tool, h ToolHandler) {
+	t.mx.Lock()
+	defer t.mx.Unlock()
+
+	if _, ok := t.handlers[tool.Name]; !ok {
+		t.tools = append(t.tools, tool)
+	}
+	t.handlers[tool.Name] = h
+}
+
+// Remove drops tools from the toolbox.
+func (t *Toolbox) Remove(names ...string) {
+	t.mx.Lock()
+	defer t.mx.Unlock()
+
+	tt := t.tools[:0]
+	for _, tool := range t.tools {
+		if slices.Contains(names, tool.Name) {
+			delete(t.handlers, tool.Name)
+			continue
+		}
+		tt = append(tt, tool)
+	}
+	t.tools = tt
+}
+
+// Tools returns the tool definitions.
+func (t *Toolbox) Tools() []Tool {
+	if t == nil {
+		return nil
+	}
+	t.mx.RLock()
+	defer t.mx.RUnlock()
+
+	return append([]Tool(nil), t.tools...)
+}
+
+// Run executes the given tool invocations and returns their results.
+func (t *Toolbox) Run(ctx context.Context, uses []ContentBlock, fn ToolFunc) []ContentBlock {
+	res := make([]ContentBlock, 0, len(uses))
+	for _, u := range uses {
+		call := ToolCall{Name: u.Name, Input: string(u.Input)}
+		call.Result, call.Err = t.run(ctx, u)
+		if fn != nil {
+			fn(call)
+		}
+		b := ContentBlock{
+			Type:      BlockToolResult,
+			ToolUseID: u.ID,
+			Content:   call.Result,
+		}
+		if call.Err != nil {
+			b.Content, b.IsError = call.Err.Error(), true
+		}
+		res = append(res, b)
+	}
+
+	return res
+}
+
+func (t *Toolbox) run(ctx context.Context, u ContentBlock) (string, error) {
+	t.mx.RLock()
+	h, ok := t.handlers[u.Name]
+	t.mx.RUnlock()
+	if !ok {
+		return "", fmt.Errorf("unknown tool %q", u.Name)
+	}
+
+	return h(ctx, u.Input)
+}
+
+// Converse streams a conversation turn, running any tools the model requests
+// until it produces a final answer. It returns the messages generated during
+// the turn, the answer carrying the usage of the whole turn. On error the
+// returned messages hold the completed exchanges plus any partial answer so
+// the conversation remains valid.
+func (c *Client) Converse(ctx context.Context, system string, messages []Message, tb *Toolbox, fn StreamFunc, tfn ToolFunc) ([]Message, error) {
+	var (
+		turn  []Message
+		usage Usage
+	)
+	for range maxToolRounds {
+		req := c.request(system, append(append([]Message(nil), messages...), turn...), tb)
+		resp, err := c.stream(ctx, req, fn)
+		if resp != nil {
+			usage.Add(resp.Usage)
+		}
+		if err != nil {
+			if resp != nil && resp.GetText() != "" {
+				turn = append(turn, Message{Role: "assistant", Content: resp.GetText(), Usage: &usage})
+			}
+			return turn, err
+		}
+		uses := resp.ToolUses()
+		if resp.StopReason != stopToolUse || len(uses) == 0 {
+			return append(turn, Message{Role: "assistant", Content: resp.GetText(), Usage: &usage}), nil
+		}
+		turn = append(turn,
+			Message{Role: "assistant", Blocks: resp.Content},
+			Message{Role: "user", Blocks: tb.Run(ctx, uses, tfn)},
+		)
+	}
+
+	return turn, errors.New("too many tool rounds without an answer")
+}
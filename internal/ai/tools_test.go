@@ -0,0 +1,77 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of K9s
+
+package ai_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/quentincherifi/c9s/internal/ai"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestToolboxRun(t *testing.T) {
+	tb := ai.NewToolbox()
+	tb.Register(ai.Tool{Name: "echo"}, func(_ context.Context, in json.RawMessage) (string, error) {
+		return string(in), nil
+	})
+	tb.Register(ai.Tool{Name: "boom"}, func(context.Context, json.RawMessage) (string, error) {
+		return "", errors.New("kaboom")
+	})
+	assert.Len(t, tb.Tools(), 2)
+
+	var calls []ai.ToolCall
+	rr := tb.Run(context.Background(), []ai.ContentBlock{
+		{Type: ai.BlockToolUse, ID: "1", Name: "echo", Input: json.RawMessage(`{"a":1}`)},
+		{Type: ai.BlockToolUse, ID: "2", Name: "boom", Input: json.RawMessage(`{}`)},
+		{Type: ai.BlockToolUse, ID: "3", Name: "nope", Input: json.RawMessage(`{}`)},
+	}, func(c ai.ToolCall) {
+		calls = append(calls, c)
+	})
+
+	require.Len(t, rr, 3)
+	assert.Equal(t, ai.ContentBlock{Type: ai.BlockToolResult, ToolUseID: "1", Content: `{"a":1}`}, rr[0])
+	assert.Equal(t, ai.ContentBlock{Type: ai.BlockToolResult, ToolUseID: "2", Content: "kaboom", IsError: true}, rr[1])
+	assert.Equal(t, ai.ContentBlock{Type: ai.BlockToolResult, ToolUseID: "3", Content: `unknown tool "nope"`, IsError: true}, rr[2])
+
+	require.Len(t, calls, 3)
+	assert.Equal(t, "echo", calls[0].Name)
+	assert.Equal(t, `{"a":1}`, calls[0].Input)
+	assert.Error(t, calls[1].Err)
+}
+
+func TestMessageJSON(t *testing.T) {
+	uu := map[string]struct {
+		m ai.Message
+		e string
+	}{
+		"text": {
+			m: ai.Message{Role: "user", Content: "hello"},
+			e: `{"role":"user","content":"hello"}`,
+		},
+		"blocks": {
+			m: ai.Message{Role: "assistant", Blocks: []ai.ContentBlock{
+				{Type: ai.BlockText, Text: "Let me look"},
+				{Type: ai.BlockToolUse, ID: "t1", Name: "get_logs", Input: json.RawMessage(`{"name":"p1"}`)},
+			}},
+			e: `{"role":"assistant","content":[{"type":"text","text":"Let me look"},{"type":"tool_use","id":"t1","name":"get_logs","input":{"name":"p1"}}]}`,
+		},
+	}
+
+	for k := range uu {
+		u := uu[k]
+		t.Run(k, func(t *testing.T) {
+			bb, err := json.Marshal(u.m)
+			require.NoError(t, err)
+			assert.JSONEq(t, u.e, string(bb))
+
+			var m ai.Message
+			require.NoError(t, json.Unmarshal(bb, &m))
+			assert.Equal(t, u.m, m)
+		})
+	}
+}
@@ -0,0 +1,242 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of K9s
+
+package ai
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/quentincherifi/c9s/internal/config"
+	"github.com/quentincherifi/c9s/internal/slogs"
+)
+
+const (
+	dayFmt   = "2006-01-02"
+	monthFmt = "2006-01"
+
+	// tallyDays tracks how many days of daily usage are kept on disk.
+	tallyDays = 62
+
+	// badTallyExt is appended to corrupt tally files moved aside.
+	badTallyExt = ".bad"
+)
+
+// ErrBudgetExceeded indicates a token budget was used up.
+var ErrBudgetExceeded = errors.New("AI token budget exceeded")
+
+// ledgerLocks serializes tally file accesses by path within this process.
+// Clients are created per request and each carries its own ledger. Other
+// instances are not locked out, so concurrent updates from several instances
+// may lose one another.
+var ledgerLocks sync.Map
+
+func ledgerLock(path string) *sync.Mutex {
+	mx, _ := ledgerLocks.LoadOrStore(path, new(sync.Mutex))
+
+	return mx.(*sync.Mutex)
+}
+
+// Total returns the total number of tokens consumed.
+func (u Usage) Total() int {
+	return u.InputTokens + u.OutputTokens
+}
+
+// Add accumulates usage.
+func (u *Usage) Add(o Usage) {
+	u.InputTokens += o.InputTokens
+	u.OutputTokens += o.OutputTokens
+}
+
+// String returns a human readable usage.
+func (u Usage) String() string {
+	return fmt.Sprintf("%d tokens (%d in, %d out)", u.Total(), u.InputTokens, u.OutputTokens)
+}
+
+// TotalUsage returns the tokens consumed producing the given messages.
+func TotalUsage(mm []Message) Usage {
+	var u Usage
+	for _, m := range mm {
+		if m.Usage != nil {
+			u.Add(*m.Usage)
+		}
+	}
+
+	return u
+}
+
+// Tally tracks token usage per day and per month.
+type Tally struct {
+	Days   map[string]Usage `json:"days"`
+	Months map[string]Usage `json:"months"`
+}
+
+// NewTally returns a new empty tally.
+func NewTally() *Tally {
+	return &Tally{
+		Days:   make(map[string]Usage),
+		Months: make(map[string]Usage),
+	}
+}
+
+// Day returns the usage for the day of the given time.
+func (t *Tally) Day(at time.Time) Usage {
+	return t.Days[at.Format(dayFmt)]
+}
+
+// Month returns the usage for the month of the given time.
+func (t *Tally) Month(at time.Time) Usage {
+	return t.Months[at.Format(monthFmt)]
+}
+
+// Add records usage at the given time. Daily entries older than a couple
+// of months are dropped.
+func (t *Tally) Add(at time.Time, u Usage) {
+	d, m := t.Days[at.Format(dayFmt)], t.Months[at.Format(monthFmt)]
+	d.Add(u)
+	m.Add(u)
+	t.Days[at.Format(dayFmt)], t.Months[at.Format(monthFmt)] = d, m
+
+	cutoff := at.AddDate(0, 0, -tallyDays).Format(dayFmt)
+	for k := range t.Days {
+		if k < cutoff {
+			delete(t.Days, k)
+		}
+	}
+}
+
+// Ledger keeps a running usage tally on disk and enforces token budgets.
+type Ledger struct {
+	path   string
+	budget *config.AIBudget
+	mx     *sync.Mutex
+}
+
+// NewLedger returns a ledger backed by the given file. Ledgers sharing a
+// file share its lock.
+func NewLedger(path string, b *config.AIBudget) *Ledger {
+	return &Ledger{path: path, budget: b, mx: ledgerLock(path)}
+}
+
+// Tally returns the usage recorded so far.
+func (l *Ledger) Tally() (*Tally, error) {
+	l.mx.Lock()
+	defer l.mx.Unlock()
+
+	return l.load()
+}
+
+// Record adds usage to the tally. The file is reloaded first so updates from
+// other instances are picked up, though not serialized with them.
+func (l *Ledger) Record(at time.Time, u Usage) error {
+	if u.Total() == 0 {
+		return nil
+	}
+
+	l.mx.Lock()
+	defer l.mx.Unlock()
+
+	t, err := l.load()
+	if err != nil {
+		return err
+	}
+	t.Add(at, u)
+
+	return l.save(t)
+}
+
+// Check verifies the budgets at the given time. It returns a warning once
+// a budget is nearly used up and an error once it is exceeded.
+func (l *Ledger) Check(at time.Time) (string, error) {
+	if l.budget == nil {
+		return "", nil
+	}
+	t, err := l.Tally()
+	if err != nil {
+		return "", err
+	}
+
+	var warn string
+	for _, b := range []struct {
+		kind  string
+		used  int
+		limit int
+	}{
+		{kind: "daily", used: t.Day(at).Total(), limit: l.budget.DailyTokens},
+		{kind: "monthly", used: t.Month(at).Total(), limit: l.budget.MonthlyTokens},
+	} {
+		if b.limit <= 0 {
+			continue
+		}
+		if b.used >= b.limit {
+			return "", fmt.Errorf("%w: %d of %d %s tokens used", ErrBudgetExceeded, b.used, b.limit, b.kind)
+		}
+		if warn == "" && b.used*100 >= b.limit*l.budget.GetWarnPercent() {
+			warn = fmt.Sprintf("%d%% of the %s token budget used (%d of %d)", b.used*100/b.limit, b.kind, b.used, b.limit)
+		}
+	}
+
+	return warn, nil
+}
+
+// load reads the tally. An unreadable tally must not lock users out of the
+// assistant, so it starts over from an empty one, a corrupt file moved aside.
+func (l *Ledger) load() (*Tally, error) {
+	t := NewTally()
+	bb, err := os.ReadFile(l.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return t, nil
+	}
+	if err != nil {
+		slog.Warn("Unable to read usage tally", slogs.Path, l.path, slogs.Error, err)
+		return t, nil
+	}
+	if err := json.Unmarshal(bb, t); err != nil {
+		slog.Warn("Corrupt usage tally, starting over", slogs.Path, l.path, slogs.Error, err)
+		if err := os.Rename(l.path, l.path+badTallyExt); err != nil {
+			slog.Warn("Unable to move corrupt usage tally aside", slogs.Path, l.path, slogs.Error, err)
+		}
+		return NewTally(), nil
+	}
+	if t.Days == nil {
+		t.Days = make(map[string]Usage)
+	}
+	if t.Months == nil {
+		t.Months = make(map[string]Usage)
+	}
+
+	return t, nil
+}
+
+// save writes the tally through a temporary file so concurrent readers, i.e.
+// other instances, never see a partial file.
+func (l *Ledger) save(t *Tally) error {
+	dir := filepath.Dir(l.path)
+	if err := os.MkdirAll(dir, SessionDirMod); err != nil {
+		return err
+	}
+	bb, err := json.MarshalIndent(t, "", "  ")
+	if err != nil {
+		return err
+	}
+	f, err := os.CreateTemp(dir, filepath.Base(l.path)+".*")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = os.Remove(f.Name()) }()
+	if _, err := f.Write(bb); err != nil {
+		_ = f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(f.Name(), l.path)
+}
@@ -0,0 +1,182 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of K9s
+
+package ai_test
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/quentincherifi/c9s/internal/ai"
+	"github.com/quentincherifi/c9s/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTallyAdd(t *testing.T) {
+	at := time.Date(2024, 3, 15, 10, 0, 0, 0, time.UTC)
+	tl := ai.NewTally()
+	tl.Add(at.AddDate(0, -3, 0), ai.Usage{InputTokens: 1})
+	tl.Add(at.Add(-time.Hour), ai.Usage{InputTokens: 10, OutputTokens: 5})
+	tl.Add(at, ai.Usage{InputTokens: 20, OutputTokens: 5})
+
+	assert.Equal(t, ai.Usage{InputTokens: 30, OutputTokens: 10}, tl.Day(at))
+	assert.Equal(t, ai.Usage{InputTokens: 30, OutputTokens: 10}, tl.Month(at))
+	assert.Len(t, tl.Days, 1)
+	assert.Len(t, tl.Months, 2)
+}
+
+func TestLedgerRecord(t *testing.T) {
+	at := time.Date(2024, 3, 15, 10, 0, 0, 0, time.UTC)
+	path := filepath.Join(t.TempDir(), "usage", "claude-usage.json")
+
+	l1, l2 := ai.NewLedger(path, nil), ai.NewLedger(path, nil)
+	require.NoError(t, l1.Record(at, ai.Usage{InputTokens: 100, OutputTokens: 20}))
+	require.NoError(t, l2.Record(at, ai.Usage{InputTokens: 50, OutputTokens: 10}))
+	require.NoError(t, l1.Record(at, ai.Usage{}))
+
+	tl, err := l1.Tally()
+	require.NoError(t, err)
+	assert.Equal(t, 180, tl.Day(at).Total())
+	assert.Equal(t, 180, tl.Month(at).Total())
+}
+
+func TestLedgerRecordConcurrent(t *testing.T) {
+	at := time.Date(2024, 3, 15, 10, 0, 0, 0, time.UTC)
+	path := filepath.Join(t.TempDir(), "claude-usage.json")
+
+	var wg sync.WaitGroup
+	for range 20 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, ai.NewLedger(path, nil).Record(at, ai.Usage{InputTokens: 10}))
+		}()
+	}
+	wg.Wait()
+
+	tl, err := ai.NewLedger(path, nil).Tally()
+	require.NoError(t, err)
+	assert.Equal(t, 200, tl.Day(at).Total())
+	ee, err := os.ReadDir(filepath.Dir(path))
+	require.NoError(t, err)
+	assert.Len(t, ee, 1)
+}
+
+func TestLedgerCorrupt(t *testing.T) {
+	at := time.Date(2024, 3, 15, 10, 0, 0, 0, time.UTC)
+	path := filepath.Join(t.TempDir(), "claude-usage.json")
+	require.NoError(t, os.WriteFile(path, []byte("{days: nope"), 0o600))
+
+	l := ai.NewLedger(path, &config.AIBudget{DailyTokens: 1_000})
+	_, err := l.Check(at)
+	require.NoError(t, err)
+	require.NoError(t, l.Record(at, ai.Usage{InputTokens: 10}))
+
+	tl, err := l.Tally()
+	require.NoError(t, err)
+	assert.Equal(t, 10, tl.Day(at).Total())
+	bb, err := os.ReadFile(path + ".bad")
+	require.NoError(t, err)
+	assert.Equal(t, "{days: nope", string(bb))
+}
+
+func TestLedgerCheck(t *testing.T) {
+	at := time.Date(2024, 3, 15, 10, 0, 0, 0, time.UTC)
+
+	uu := map[string]struct {
+		budget *config.AIBudget
+		used   int
+		warn   string
+		err    string
+	}{
+		"no-budget": {
+			used: 1_000,
+		},
+		"under": {
+			budget: &config.AIBudget{DailyTokens: 1_000},
+			used:   500,
+		},
+		"warn": {
+			budget: &config.AIBudget{DailyTokens: 1_000},
+			used:   850,
+			warn:   "85% of the daily token budget used (850 of 1000)",
+		},
+		"custom-warn": {
+			budget: &config.AIBudget{MonthlyTokens: 1_000, WarnPercent: 50},
+			used:   600,
+			warn:   "60% of the monthly token budget used (600 of 1000)",
+		},
+		"daily-exceeded": {
+			budget: &config.AIBudget{DailyTokens: 1_000, MonthlyTokens: 10_000},
+			used:   1_000,
+			err:    "AI token budget exceeded: 1000 of 1000 daily tokens used",
+		},
+		"monthly-exceeded": {
+			budget: &config.AIBudget{MonthlyTokens: 900},
+			used:   1_000,
+			err:    "AI token budget exceeded: 1000 of 900 monthly tokens used",
+		},
+	}
+
+	for k := range uu {
+		u := uu[k]
+		t.Run(k, func(t *testing.T) {
+			l := ai.NewLedger(filepath.Join(t.TempDir(), "usage.json"), u.budget)
+			require.NoError(t, l.Record(at, ai.Usage{InputTokens: u.used}))
+
+			warn, err := l.Check(at)
+			if u.err != "" {
+				require.ErrorIs(t, err, ai.ErrBudgetExceeded)
+				assert.EqualError(t, err, u.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, u.warn, warn)
+		})
+	}
+}
+
+func TestUsageJSON(t *testing.T) {
+	var u ai.Usage
+	require.NoError(t, json.Unmarshal([]byte(`{"input_tokens":3,"output_tokens":4}`), &u))
+	assert.Equal(t, "7 tokens (3 in, 4 out)", u.String())
+}
+
+type usageProvider struct {
+	calls int
+}
+
+func (*usageProvider) Name() string { return "usage" }
+
+func (p *usageProvider) Send(context.Context, ai.Request) (*ai.Response, error) {
+	p.calls++
+	return &ai.Response{Usage: ai.Usage{InputTokens: 60, OutputTokens: 40}}, nil
+}
+
+func (p *usageProvider) Stream(ctx context.Context, req ai.Request, _ ai.StreamFunc) (*ai.Response, error) {
+	resp, err := p.Send(ctx, req)
+	resp.StopReason, resp.Content = "end_turn", []ai.ContentBlock{{Type: ai.BlockText, Text: "ok"}}
+
+	return resp, err
+}
+
+func TestClientBudget(t *testing.T) {
+	p := new(usageProvider)
+	c := ai.NewProviderClient(p, "m", 10)
+	c.SetLedger(ai.NewLedger(filepath.Join(t.TempDir(), "usage.json"), &config.AIBudget{DailyTokens: 100}))
+
+	turn, err := c.Converse(context.Background(), "", []ai.Message{{Role: "user", Content: "hi"}}, nil, nil, nil)
+	require.NoError(t, err)
+	require.Len(t, turn, 1)
+	assert.Equal(t, &ai.Usage{InputTokens: 60, OutputTokens: 40}, turn[0].Usage)
+
+	_, err = c.Converse(context.Background(), "", []ai.Message{{Role: "user", Content: "again"}}, nil, nil, nil)
+	require.ErrorIs(t, err, ai.ErrBudgetExceeded)
+	assert.Equal(t, 1, p.calls)
+}
@@ -0,0 +1,132 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of K9s
+
+package ai
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/quentincherifi/c9s/internal"
+	"github.com/quentincherifi/c9s/internal/client"
+	"github.com/quentincherifi/c9s/internal/config"
+	"github.com/quentincherifi/c9s/internal/dao"
+	"github.com/quentincherifi/c9s/internal/render"
+	"github.com/quentincherifi/c9s/internal/xray"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+type treeRenderer interface {
+	Render(ctx context.Context, ns string, o any) error
+}
+
+// treeRenderers tracks the resources an xray tree can be built for.
+var treeRenderers = map[*client.GVR]treeRenderer{
+	client.DpGVR:  new(xray.Deployment),
+	client.StsGVR: new(xray.StatefulSet),
+	client.DsGVR:  new(xray.DaemonSet),
+	client.SvcGVR: new(xray.Service),
+	client.PodGVR: new(xray.Pod),
+}
+
+// treeStatuses spells out xray node statuses.
+var treeStatuses = map[string]string{
+	xray.OkStatus:         "ok",
+	xray.ToastStatus:      "unhealthy",
+	xray.CompletedStatus:  "completed",
+	xray.MissingRefStatus: "missing",
+}
+
+// HasTree checks if a dependency tree can be built for a resource.
+func HasTree(gvr *client.GVR) bool {
+	_, ok := treeRenderers[gvr]
+
+	return ok
+}
+
+// DependencyTree builds the xray tree of a resource. The returned node is
+// nil when the resource has no dependents.
+func DependencyTree(ctx context.Context, f dao.Factory, gvr *client.GVR, path string) (*xray.TreeNode, error) {
+	re, ok := treeRenderers[gvr]
+	if !ok {
+		return nil, fmt.Errorf("no dependency tree available for %s", gvr)
+	}
+	o, err := f.Get(gvr, path, true, labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	var res any = o
+	if gvr == client.PodGVR {
+		raw, ok := o.(*unstructured.Unstructured)
+		if !ok {
+			return nil, fmt.Errorf("expecting *Unstructured but got %T", o)
+		}
+		res = &render.PodWithMetrics{Raw: raw}
+	}
+
+	root := xray.NewTreeNode(gvr, gvr.R())
+	ctx = context.WithValue(ctx, xray.KeyParent, root)
+	ctx = context.WithValue(ctx, internal.KeyFactory, f)
+	ns, _ := client.Namespaced(path)
+	if err := re.Render(ctx, ns, res); err != nil {
+		return nil, err
+	}
+	n := root.Find(gvr, path)
+	if n != nil {
+		n.Sort()
+	}
+
+	return n, nil
+}
+
+// TreeText serializes a dependency tree one node per line, indented by
+// depth, with each node kind, name, status and details.
+func TreeText(n *xray.TreeNode) string {
+	if n == nil {
+		return ""
+	}
+	var ll []string
+	treeLines(n, 0, &ll)
+
+	return strings.Join(ll, "\n")
+}
+
+func treeLines(n *xray.TreeNode, depth int, ll *[]string) {
+	kind := n.GVR.R()
+	if m, err := dao.MetaAccess.MetaFor(n.GVR); err == nil && m.SingularName != "" {
+		kind = m.SingularName
+	}
+	status, ok := treeStatuses[n.Extras[xray.StatusKey]]
+	if !ok {
+		status = n.Extras[xray.StatusKey]
+	}
+	l := fmt.Sprintf("%s%s %s [%s]", strings.Repeat("  ", depth), kind, n.ID, status)
+	if info := n.Extras[xray.InfoKey]; info != "" {
+		l += " " + info
+	}
+	*ll = append(*ll, l)
+	for _, c := range n.Children {
+		treeLines(c, depth+1, ll)
+	}
+}
+
+// LoadTree shares the dependency tree of a resource, trimmed to the
+// configured budget. Resources without dependency trees are skipped.
+func (k *K8sContext) LoadTree(ctx context.Context, f dao.Factory, gvr *client.GVR, path string, cfg *config.AI) error {
+	if !HasTree(gvr) {
+		return nil
+	}
+	n, err := DependencyTree(ctx, f, gvr, path)
+	if err != nil {
+		return err
+	}
+	if n == nil {
+		k.Tree = ""
+		return nil
+	}
+	k.Tree = TrimLines(strings.Split(TreeText(n), "\n"), cfg.GetMaxTreeSize())
+
+	return nil
+}
@@ -0,0 +1,49 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of K9s
+
+package ai_test
+
+import (
+	"testing"
+
+	"github.com/quentincherifi/c9s/internal/ai"
+	"github.com/quentincherifi/c9s/internal/client"
+	"github.com/quentincherifi/c9s/internal/xray"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTreeText(t *testing.T) {
+	dp := xray.NewTreeNode(client.DpGVR, "default/fred")
+	dp.Extras[xray.StatusKey], dp.Extras[xray.InfoKey] = xray.ToastStatus, "0/1/1"
+	po := xray.NewTreeNode(client.PodGVR, "default/fred-1")
+	po.Extras[xray.StatusKey], po.Extras[xray.InfoKey] = xray.ToastStatus, "0/1"
+	co := xray.NewTreeNode(client.CoGVR, "default/fred-1/app")
+	sec := xray.NewTreeNode(client.SecGVR, "default/db-creds")
+	sec.Extras[xray.StatusKey] = xray.MissingRefStatus
+	co.Add(sec)
+	po.Add(co)
+	dp.Add(po)
+
+	assert.Equal(t, "", ai.TreeText(nil))
+	assert.Equal(t, `deployments default/fred [unhealthy] 0/1/1
+  pods default/fred-1 [unhealthy] 0/1
+    containers default/fred-1/app [ok]
+      secrets default/db-creds [missing]`, ai.TreeText(dp))
+}
+
+func TestHasTree(t *testing.T) {
+	assert.True(t, ai.HasTree(client.DpGVR))
+	assert.True(t, ai.HasTree(client.SvcGVR))
+	assert.False(t, ai.HasTree(client.CmGVR))
+}
+
+func TestBuildSystemPromptTree(t *testing.T) {
+	p, err := ai.BuildSystemPrompt(&ai.K8sContext{
+		SelectedResource: "default/fred",
+		Tree:             "deployments default/fred [ok] 1/1/0",
+	})
+	require.NoError(t, err)
+	assert.Contains(t, p, "Dependency Tree, one resource per line")
+	assert.Contains(t, p, "deployments default/fred [ok] 1/1/0")
+}
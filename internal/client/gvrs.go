@@ -62,6 +62,7 @@ var (
 	PfGVR  = NewGVR("portforwards")
 	SdGVR  = NewGVR("screendumps")
 	BeGVR  = NewGVR("benchmarks")
+	CsGVR  = NewGVR("claudesessions")
 	AliGVR = NewGVR("aliases")
 	XGVR   = NewGVR("xrays")
 	HlpGVR = NewGVR("help")
@@ -95,6 +96,7 @@ var reservedGVRs = sets.New(
 	PfGVR,
 	SdGVR,
 	BeGVR,
+	CsGVR,
 	AliGVR,
 	XGVR,
 	HlpGVR,
@@ -3,22 +3,214 @@
 
 package config
 
-import "os"
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+
+	"github.com/quentincherifi/c9s/internal/config/data"
+)
 
 const (
 	// DefaultAIModel is the default Claude model to use.
 	DefaultAIModel = "claude-sonnet-4-20250514"
 	// DefaultAIMaxTokens is the default max tokens for AI responses.
 	DefaultAIMaxTokens = 4096
+	// DefaultAIModelAlias resets a model switch to the configured model.
+	DefaultAIModelAlias = "default"
+	// DefaultAIMaxYAMLSize is the default byte budget for resource manifests.
+	DefaultAIMaxYAMLSize = 16 * 1024
+	// DefaultAIMaxEventsSize is the default byte budget for resource events.
+	DefaultAIMaxEventsSize = 4 * 1024
+	// DefaultAIMaxLogLines is the default number of log lines to explain.
+	DefaultAIMaxLogLines = 500
+	// DefaultAIMaxLogsSize is the default byte budget for log lines.
+	DefaultAIMaxLogsSize = 32 * 1024
+	// DefaultAIMaxRows is the default number of table rows shared.
+	DefaultAIMaxRows = 50
+	// DefaultAIMaxTreeSize is the default byte budget for dependency trees.
+	DefaultAIMaxTreeSize = 4 * 1024
+	// DefaultAIMaxRetries is the default number of retries for throttled requests.
+	DefaultAIMaxRetries = 3
+	// DefaultAIMaxRepairs is the default number of repair rounds of generated manifests.
+	DefaultAIMaxRepairs = 3
+
+	// DefaultAIRunbookExcerpts is the default number of runbook excerpts shared.
+	DefaultAIRunbookExcerpts = 3
+	// DefaultAIRunbooksSize is the default byte budget for runbook excerpts.
+	DefaultAIRunbooksSize = 8 * 1024
+
+	// DefaultAICompactThreshold is the default estimated conversation size, in
+	// tokens, past which older turns are summarized.
+	DefaultAICompactThreshold = 50_000
+	// DefaultAICompactKeep is the default number of recent messages never summarized.
+	DefaultAICompactKeep = 6
+
+	// DefaultAIBudgetWarnPercent is the default budget share past which users are warned.
+	DefaultAIBudgetWarnPercent = 80
+
+	// AIProviderAnthropic represents the Anthropic messages API.
+	AIProviderAnthropic = "anthropic"
+	// AIProviderOpenAI represents an OpenAI compatible chat completions API.
+	AIProviderOpenAI = "openai"
 )
 
 // AI tracks AI/Claude configuration options.
 type AI struct {
-	Enabled   bool   `json:"enabled" yaml:"enabled"`
-	APIKey    string `json:"apiKey,omitempty" yaml:"apiKey,omitempty"`
-	APIKeyEnv string `json:"apiKeyEnv,omitempty" yaml:"apiKeyEnv,omitempty"`
-	Model     string `json:"model,omitempty" yaml:"model,omitempty"`
-	MaxTokens int    `json:"maxTokens,omitempty" yaml:"maxTokens,omitempty"`
+	Enabled       bool              `json:"enabled" yaml:"enabled"`
+	APIKey        string            `json:"apiKey,omitempty" yaml:"apiKey,omitempty"`
+	APIKeyEnv     string            `json:"apiKeyEnv,omitempty" yaml:"apiKeyEnv,omitempty"`
+	APIKeyCommand string            `json:"apiKeyCommand,omitempty" yaml:"apiKeyCommand,omitempty"`
+	APIKeyFile    string            `json:"apiKeyFile,omitempty" yaml:"apiKeyFile,omitempty"`
+	Model         string            `json:"model,omitempty" yaml:"model,omitempty"`
+	MaxTokens     int               `json:"maxTokens,omitempty" yaml:"maxTokens,omitempty"`
+	Temperature   *float64          `json:"temperature,omitempty" yaml:"temperature,omitempty"`
+	SystemPrompt  string            `json:"systemPrompt,omitempty" yaml:"systemPrompt,omitempty"`
+	Models        AIModels          `json:"models,omitempty" yaml:"models,omitempty"`
+	MaxYAMLSize   int               `json:"maxYAMLSize,omitempty" yaml:"maxYAMLSize,omitempty"`
+	MaxEventsSize int               `json:"maxEventsSize,omitempty" yaml:"maxEventsSize,omitempty"`
+	MaxLogLines   int               `json:"maxLogLines,omitempty" yaml:"maxLogLines,omitempty"`
+	MaxLogsSize   int               `json:"maxLogsSize,omitempty" yaml:"maxLogsSize,omitempty"`
+	MaxRetries    int               `json:"maxRetries,omitempty" yaml:"maxRetries,omitempty"`
+	MaxRepairs    int               `json:"maxRepairs,omitempty" yaml:"maxRepairs,omitempty"`
+	MaxRows       int               `json:"maxRows,omitempty" yaml:"maxRows,omitempty"`
+	MaxTreeSize   int               `json:"maxTreeSize,omitempty" yaml:"maxTreeSize,omitempty"`
+	Redact        []AIRedactRule    `json:"redact,omitempty" yaml:"redact,omitempty"`
+	Provider      string            `json:"provider,omitempty" yaml:"provider,omitempty"`
+	BaseURL       string            `json:"baseURL,omitempty" yaml:"baseURL,omitempty"`
+	Headers       map[string]string `json:"headers,omitempty" yaml:"headers,omitempty"`
+	TLS           *AITLS            `json:"tls,omitempty" yaml:"tls,omitempty"`
+	Questions     map[string]string `json:"questions,omitempty" yaml:"questions,omitempty"`
+	AutoAsk       bool              `json:"autoAsk,omitempty" yaml:"autoAsk,omitempty"`
+	Budget        *AIBudget         `json:"budget,omitempty" yaml:"budget,omitempty"`
+	Runbooks      *AIRunbooks       `json:"runbooks,omitempty" yaml:"runbooks,omitempty"`
+	Compaction    *AICompaction     `json:"compaction,omitempty" yaml:"compaction,omitempty"`
+
+	// forced tracks a provider forced by a context policy.
+	forced bool
+}
+
+// AIModels tracks model aliases by name.
+type AIModels map[string]AIModel
+
+// AIModel tracks the settings of a model alias, i.e. a cheap and fast model
+// for quick questions.
+type AIModel struct {
+	Model        string   `json:"model,omitempty" yaml:"model,omitempty"`
+	MaxTokens    int      `json:"maxTokens,omitempty" yaml:"maxTokens,omitempty"`
+	Temperature  *float64 `json:"temperature,omitempty" yaml:"temperature,omitempty"`
+	SystemPrompt string   `json:"systemPrompt,omitempty" yaml:"systemPrompt,omitempty"`
+}
+
+// String returns the model along with its non default settings.
+func (m AIModel) String() string {
+	s := m.Model
+	if m.MaxTokens > 0 {
+		s += " max " + strconv.Itoa(m.MaxTokens) + " tokens"
+	}
+	if m.Temperature != nil {
+		s += " temperature " + strconv.FormatFloat(*m.Temperature, 'f', -1, 64)
+	}
+	if m.SystemPrompt != "" {
+		s += " with custom instructions"
+	}
+
+	return s
+}
+
+// AIRunbooks tracks local Markdown runbooks used to ground answers.
+type AIRunbooks struct {
+	Dirs        []string `json:"dirs,omitempty" yaml:"dirs,omitempty"`
+	MaxExcerpts int      `json:"maxExcerpts,omitempty" yaml:"maxExcerpts,omitempty"`
+	MaxSize     int      `json:"maxSize,omitempty" yaml:"maxSize,omitempty"`
+}
+
+// GetDirs returns the runbook directories, environment variables expanded.
+func (r *AIRunbooks) GetDirs() []string {
+	if r == nil {
+		return nil
+	}
+	dd := make([]string, 0, len(r.Dirs))
+	for _, d := range r.Dirs {
+		if d = os.ExpandEnv(d); d != "" {
+			dd = append(dd, d)
+		}
+	}
+
+	return dd
+}
+
+// GetMaxExcerpts returns the number of excerpts shared, defaulting if not set.
+func (r *AIRunbooks) GetMaxExcerpts() int {
+	if r != nil && r.MaxExcerpts > 0 {
+		return r.MaxExcerpts
+	}
+	return DefaultAIRunbookExcerpts
+}
+
+// GetMaxSize returns the runbook excerpts budget, defaulting if not set.
+func (r *AIRunbooks) GetMaxSize() int {
+	if r != nil && r.MaxSize > 0 {
+		return r.MaxSize
+	}
+	return DefaultAIRunbooksSize
+}
+
+// AICompaction tracks when older conversation turns are summarized.
+type AICompaction struct {
+	Threshold int `json:"threshold,omitempty" yaml:"threshold,omitempty"`
+	Keep      int `json:"keep,omitempty" yaml:"keep,omitempty"`
+}
+
+// GetThreshold returns the estimated conversation size, in tokens, past
+// which older turns are summarized. A negative threshold disables compaction.
+func (c *AICompaction) GetThreshold() int {
+	switch {
+	case c == nil || c.Threshold == 0:
+		return DefaultAICompactThreshold
+	case c.Threshold < 0:
+		return 0
+	default:
+		return c.Threshold
+	}
+}
+
+// GetKeep returns the number of recent messages never summarized.
+func (c *AICompaction) GetKeep() int {
+	if c != nil && c.Keep > 0 {
+		return c.Keep
+	}
+	return DefaultAICompactKeep
+}
+
+// AIBudget tracks token budgets. Requests are refused once a budget is used up.
+type AIBudget struct {
+	DailyTokens   int `json:"dailyTokens,omitempty" yaml:"dailyTokens,omitempty"`
+	MonthlyTokens int `json:"monthlyTokens,omitempty" yaml:"monthlyTokens,omitempty"`
+	WarnPercent   int `json:"warnPercent,omitempty" yaml:"warnPercent,omitempty"`
+}
+
+// GetWarnPercent returns the budget share past which users are warned.
+func (b *AIBudget) GetWarnPercent() int {
+	if b.WarnPercent > 0 && b.WarnPercent <= 100 {
+		return b.WarnPercent
+	}
+	return DefaultAIBudgetWarnPercent
+}
+
+// AITLS tracks TLS settings used to reach the AI provider.
+type AITLS struct {
+	CAFile             string `json:"caFile,omitempty" yaml:"caFile,omitempty"`
+	CertFile           string `json:"certFile,omitempty" yaml:"certFile,omitempty"`
+	KeyFile            string `json:"keyFile,omitempty" yaml:"keyFile,omitempty"`
+	InsecureSkipVerify bool   `json:"insecureSkipVerify,omitempty" yaml:"insecureSkipVerify,omitempty"`
+}
+
+// AIRedactRule tracks a custom pattern masked before content is sent to the AI.
+type AIRedactRule struct {
+	Name    string `json:"name" yaml:"name"`
+	Pattern string `json:"pattern" yaml:"pattern"`
 }
 
 // NewAI creates a new AI configuration with defaults.
@@ -30,25 +222,157 @@ func NewAI() AI {
 	}
 }
 
-// GetAPIKey returns the API key, checking config first, then env var.
-func (a *AI) GetAPIKey() string {
+// GetAPIKey returns the API key, checking the credential helper command
+// first, then the obfuscated key file, the config and finally env vars.
+func (a *AI) GetAPIKey() (string, error) {
+	if a.APIKeyCommand != "" {
+		return runAPIKeyCommand(a.APIKeyCommand)
+	}
+	if a.forced {
+		if a.APIKeyEnv != "" {
+			return os.Getenv(a.APIKeyEnv), nil
+		}
+		return "", nil
+	}
+	if key, err := LoadAPIKey(a.GetAPIKeyFile()); err != nil || key != "" {
+		return key, err
+	}
 	if a.APIKey != "" {
-		return a.APIKey
+		return a.APIKey, nil
 	}
 	if a.APIKeyEnv != "" {
-		return os.Getenv(a.APIKeyEnv)
+		return os.Getenv(a.APIKeyEnv), nil
+	}
+	if a.GetProvider() == AIProviderOpenAI {
+		return os.Getenv("OPENAI_API_KEY"), nil
 	}
-	return os.Getenv("ANTHROPIC_API_KEY")
+	return os.Getenv("ANTHROPIC_API_KEY"), nil
+}
+
+// forceProvider switches to the provider forced by a context policy. The
+// global key sources, headers, TLS settings and model are meant for the
+// global provider and are never sent to the forced one.
+func (a *AI) forceProvider(p *data.AIPolicy) {
+	a.Provider, a.BaseURL, a.Model = p.Provider, p.BaseURL, p.Model
+	a.APIKey, a.APIKeyEnv, a.APIKeyCommand, a.APIKeyFile = "", p.APIKeyEnv, p.APIKeyCommand, ""
+	a.Headers, a.Models, a.TLS, a.forced = nil, nil, nil, true
+	if p.TLS != nil {
+		a.TLS = &AITLS{
+			CAFile:             p.TLS.CAFile,
+			CertFile:           p.TLS.CertFile,
+			KeyFile:            p.TLS.KeyFile,
+			InsecureSkipVerify: p.TLS.InsecureSkipVerify,
+		}
+	}
+}
+
+// Validate checks the settings can reach a provider. A provider forced by a
+// context policy must name its own model as the default one is a Claude model.
+func (a *AI) Validate() error {
+	if a.forced && a.Model == "" {
+		return fmt.Errorf("context AI policy forces provider %q but sets no model", a.Provider)
+	}
+
+	return nil
 }
 
-// GetModel returns the model to use, defaulting if not set.
+// GetAPIKeyFile returns the obfuscated API key file location.
+func (a *AI) GetAPIKeyFile() string {
+	if a.APIKeyFile != "" {
+		return os.ExpandEnv(a.APIKeyFile)
+	}
+	return AppClaudeKeyFile
+}
+
+// GetProvider returns the AI provider, defaulting to Anthropic.
+func (a *AI) GetProvider() string {
+	if a.Provider != "" {
+		return a.Provider
+	}
+	return AIProviderAnthropic
+}
+
+// RequiresAPIKey checks if the provider can not be reached without an API key.
+// Self hosted OpenAI compatible servers often run unauthenticated.
+func (a *AI) RequiresAPIKey() bool {
+	return a.GetProvider() == AIProviderAnthropic
+}
+
+// QuestionFor returns the canned question for a resource, looked up by
+// fully qualified resource name, i.e. apps/v1/deployments, then plain name.
+func (a *AI) QuestionFor(gvr, name string) (string, bool) {
+	if q, ok := a.Questions[gvr]; ok && q != "" {
+		return q, true
+	}
+	q, ok := a.Questions[name]
+
+	return q, ok && q != ""
+}
+
+// GetModel returns the model to use, defaulting if not set. A provider forced
+// by a context policy never defaults to a Claude model.
 func (a *AI) GetModel() string {
-	if a.Model != "" {
+	if a.Model != "" || a.forced {
 		return a.Model
 	}
 	return DefaultAIModel
 }
 
+// CurrentModel returns the model settings in use.
+func (a *AI) CurrentModel() AIModel {
+	return AIModel{
+		Model:        a.GetModel(),
+		MaxTokens:    a.GetMaxTokens(),
+		Temperature:  a.Temperature,
+		SystemPrompt: a.SystemPrompt,
+	}
+}
+
+// ModelAliases returns the configured model aliases, sorted.
+func (a *AI) ModelAliases() []string {
+	aa := make([]string, 0, len(a.Models))
+	for k := range a.Models {
+		aa = append(aa, k)
+	}
+	slices.Sort(aa)
+
+	return aa
+}
+
+// IsModelAlias checks if a name matches a configured model alias.
+func (a *AI) IsModelAlias(name string) bool {
+	m, ok := a.Models[name]
+
+	return ok && m.Model != ""
+}
+
+// ResolveModel returns the settings of a model alias. Names not matching an
+// alias are taken as model names.
+func (a *AI) ResolveModel(name string) AIModel {
+	if m, ok := a.Models[name]; ok && m.Model != "" {
+		return m
+	}
+
+	return AIModel{Model: name}
+}
+
+// ApplyModel switches to a model. Settings not set by the model are kept.
+func (a *AI) ApplyModel(m AIModel) {
+	if m.Model != "" {
+		a.Model = m.Model
+	}
+	if m.MaxTokens > 0 {
+		a.MaxTokens = m.MaxTokens
+	}
+	if m.Temperature != nil {
+		t := *m.Temperature
+		a.Temperature = &t
+	}
+	if m.SystemPrompt != "" {
+		a.SystemPrompt = m.SystemPrompt
+	}
+}
+
 // GetMaxTokens returns the max tokens, defaulting if not set.
 func (a *AI) GetMaxTokens() int {
 	if a.MaxTokens > 0 {
@@ -56,3 +380,77 @@ func (a *AI) GetMaxTokens() int {
 	}
 	return DefaultAIMaxTokens
 }
+
+// GetMaxRetries returns how many times throttled or failed requests are
+// retried. A negative value disables retries.
+func (a *AI) GetMaxRetries() int {
+	switch {
+	case a.MaxRetries < 0:
+		return 0
+	case a.MaxRetries > 0:
+		return a.MaxRetries
+	default:
+		return DefaultAIMaxRetries
+	}
+}
+
+// GetMaxRepairs returns how many times a generated manifest failing its
+// dry-run is sent back for repairs. A negative value disables repairs.
+func (a *AI) GetMaxRepairs() int {
+	switch {
+	case a.MaxRepairs < 0:
+		return 0
+	case a.MaxRepairs > 0:
+		return a.MaxRepairs
+	default:
+		return DefaultAIMaxRepairs
+	}
+}
+
+// GetMaxRows returns the number of table rows shared, defaulting if not set.
+func (a *AI) GetMaxRows() int {
+	if a.MaxRows > 0 {
+		return a.MaxRows
+	}
+	return DefaultAIMaxRows
+}
+
+// GetMaxTreeSize returns the dependency tree budget, defaulting if not set.
+func (a *AI) GetMaxTreeSize() int {
+	if a.MaxTreeSize > 0 {
+		return a.MaxTreeSize
+	}
+	return DefaultAIMaxTreeSize
+}
+
+// GetMaxYAMLSize returns the resource manifest budget, defaulting if not set.
+func (a *AI) GetMaxYAMLSize() int {
+	if a.MaxYAMLSize > 0 {
+		return a.MaxYAMLSize
+	}
+	return DefaultAIMaxYAMLSize
+}
+
+// GetMaxEventsSize returns the resource events budget, defaulting if not set.
+func (a *AI) GetMaxEventsSize() int {
+	if a.MaxEventsSize > 0 {
+		return a.MaxEventsSize
+	}
+	return DefaultAIMaxEventsSize
+}
+
+// GetMaxLogLines returns the number of log lines to explain, defaulting if not set.
+func (a *AI) GetMaxLogLines() int {
+	if a.MaxLogLines > 0 {
+		return a.MaxLogLines
+	}
+	return DefaultAIMaxLogLines
+}
+
+// GetMaxLogsSize returns the log lines budget, defaulting if not set.
+func (a *AI) GetMaxLogsSize() int {
+	if a.MaxLogsSize > 0 {
+		return a.MaxLogsSize
+	}
+	return DefaultAIMaxLogsSize
+}
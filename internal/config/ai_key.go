@@ -0,0 +1,220 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of K9s
+
+package config
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"os/exec"
+	"os/user"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/quentincherifi/c9s/internal/config/data"
+	"github.com/quentincherifi/c9s/internal/slogs"
+	"golang.org/x/sync/singleflight"
+)
+
+const (
+	// apiKeyCommandTimeout caps how long a credential helper may run.
+	apiKeyCommandTimeout = 30 * time.Second
+
+	// apiKeyCacheTTL tracks how long a credential helper key is reused, so
+	// rotated or short lived keys are picked up.
+	apiKeyCacheTTL = 15 * time.Minute
+
+	apiKeyFileHeader = "c9s-key-v1:"
+	apiKeySaltSize   = 16
+)
+
+// ErrAPIKeyFile indicates an API key file could not be decrypted.
+var ErrAPIKeyFile = errors.New("unable to decrypt API key file. Use ':claude set-key' to set the key again")
+
+// cachedKey tracks a credential helper key along with its expiry.
+type cachedKey struct {
+	key     string
+	expires time.Time
+}
+
+var apiKeyCache = struct {
+	sync.Mutex
+	keys map[string]cachedKey
+	runs singleflight.Group
+}{keys: make(map[string]cachedKey)}
+
+// runAPIKeyCommand runs a credential helper and returns the first line it
+// prints. Keys are cached in memory for a while so helpers do not run on
+// every request. The cache is not locked while a helper runs as it may take
+// a while.
+func runAPIKeyCommand(command string) (string, error) {
+	apiKeyCache.Lock()
+	c, ok := apiKeyCache.keys[command]
+	apiKeyCache.Unlock()
+	if ok && time.Now().Before(c.expires) {
+		return c.key, nil
+	}
+
+	v, err, _ := apiKeyCache.runs.Do(command, func() (any, error) {
+		key, err := execAPIKeyCommand(command)
+		if err != nil {
+			return "", err
+		}
+		apiKeyCache.Lock()
+		apiKeyCache.keys[command] = cachedKey{key: key, expires: time.Now().Add(apiKeyCacheTTL)}
+		apiKeyCache.Unlock()
+
+		return key, nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return v.(string), nil
+}
+
+func execAPIKeyCommand(command string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), apiKeyCommandTimeout)
+	defer cancel()
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.CommandContext(ctx, "cmd", "/C", command)
+	} else {
+		cmd = exec.CommandContext(ctx, "sh", "-c", command)
+	}
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("api key command failed: %w: %s", err, msg)
+		}
+		return "", fmt.Errorf("api key command failed: %w", err)
+	}
+	key, _, _ := strings.Cut(stdout.String(), "\n")
+	key = strings.TrimSpace(key)
+	if key == "" {
+		return "", errors.New("api key command returned no key")
+	}
+
+	return key, nil
+}
+
+// SaveAPIKey obfuscates an API key into a file only readable by its owner.
+// The file permissions are what keeps the key private, see apiKeyCipher.
+func SaveAPIKey(path, key string) error {
+	if path == "" {
+		return errors.New("no API key file location")
+	}
+	salt := make([]byte, apiKeySaltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return err
+	}
+	aead, err := apiKeyCipher(salt)
+	if err != nil {
+		return err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return err
+	}
+	bb := append(salt, nonce...)
+	bb = aead.Seal(bb, nonce, []byte(key), nil)
+
+	if err := data.EnsureDirPath(path, data.DefaultDirMod); err != nil {
+		return err
+	}
+	raw := apiKeyFileHeader + base64.StdEncoding.EncodeToString(bb) + "\n"
+	if err := os.WriteFile(path, []byte(raw), data.DefaultFileMod); err != nil {
+		return err
+	}
+
+	return os.Chmod(path, data.DefaultFileMod)
+}
+
+// LoadAPIKey decrypts an API key file. A missing file yields no key.
+func LoadAPIKey(path string) (string, error) {
+	if path == "" {
+		return "", nil
+	}
+	raw, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	if fi, err := os.Stat(path); err == nil && runtime.GOOS != "windows" && fi.Mode().Perm()&0o077 != 0 {
+		slog.Warn("API key file is accessible by other users", slogs.Path, path)
+	}
+
+	enc, ok := strings.CutPrefix(strings.TrimSpace(string(raw)), apiKeyFileHeader)
+	if !ok {
+		return "", ErrAPIKeyFile
+	}
+	bb, err := base64.StdEncoding.DecodeString(enc)
+	if err != nil || len(bb) < apiKeySaltSize {
+		return "", ErrAPIKeyFile
+	}
+	aead, err := apiKeyCipher(bb[:apiKeySaltSize])
+	if err != nil {
+		return "", err
+	}
+	bb = bb[apiKeySaltSize:]
+	if len(bb) < aead.NonceSize() {
+		return "", ErrAPIKeyFile
+	}
+	key, err := aead.Open(nil, bb[:aead.NonceSize()], bb[aead.NonceSize():], nil)
+	if err != nil {
+		return "", ErrAPIKeyFile
+	}
+
+	return string(key), nil
+}
+
+// apiKeyCipher derives the key file cipher from a salt and the current
+// machine and user identity, so a copied key file is useless elsewhere.
+// This is obfuscation, not protection: the salt is stored in the file and
+// the identity is readable by anyone on the machine, so whoever may read
+// the file may recover the key.
+func apiKeyCipher(salt []byte) (cipher.AEAD, error) {
+	h := sha256.New()
+	h.Write(salt)
+	h.Write([]byte(machineIdentity()))
+	block, err := aes.NewCipher(h.Sum(nil))
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
+
+func machineIdentity() string {
+	var id string
+	for _, p := range []string{"/etc/machine-id", "/var/lib/dbus/machine-id"} {
+		if bb, err := os.ReadFile(p); err == nil {
+			if id = strings.TrimSpace(string(bb)); id != "" {
+				break
+			}
+		}
+	}
+	if id == "" {
+		id, _ = os.Hostname()
+	}
+	if u, err := user.Current(); err == nil {
+		return id + "/" + u.Uid
+	}
+
+	return id + "/" + os.Getenv("USER")
+}
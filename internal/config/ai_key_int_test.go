@@ -0,0 +1,44 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of K9s
+
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunAPIKeyCommandExpiry(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("credential helper test relies on sh")
+	}
+	count := filepath.Join(t.TempDir(), "count")
+	command := fmt.Sprintf("echo run >> %s; echo sk-cmd", count)
+
+	for range 2 {
+		key, err := runAPIKeyCommand(command)
+		require.NoError(t, err)
+		assert.Equal(t, "sk-cmd", key)
+	}
+	apiKeyCache.Lock()
+	c := apiKeyCache.keys[command]
+	assert.WithinDuration(t, time.Now().Add(apiKeyCacheTTL), c.expires, time.Minute)
+	c.expires = time.Now().Add(-time.Second)
+	apiKeyCache.keys[command] = c
+	apiKeyCache.Unlock()
+
+	key, err := runAPIKeyCommand(command)
+	require.NoError(t, err)
+	assert.Equal(t, "sk-cmd", key)
+	bb, err := os.ReadFile(count)
+	require.NoError(t, err)
+	assert.Equal(t, 2, strings.Count(string(bb), "run"))
+}
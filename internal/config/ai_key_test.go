@@ -0,0 +1,125 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of K9s
+
+package config_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/quentincherifi/c9s/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPIKeyFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "c9s", "claude-key")
+
+	key, err := config.LoadAPIKey(path)
+	require.NoError(t, err)
+	assert.Empty(t, key)
+
+	require.NoError(t, config.SaveAPIKey(path, "sk-ant-fred"))
+	bb, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.NotContains(t, string(bb), "sk-ant-fred")
+	if runtime.GOOS != "windows" {
+		fi, err := os.Stat(path)
+		require.NoError(t, err)
+		assert.Equal(t, os.FileMode(0o600), fi.Mode().Perm())
+	}
+
+	key, err = config.LoadAPIKey(path)
+	require.NoError(t, err)
+	assert.Equal(t, "sk-ant-fred", key)
+
+	require.NoError(t, os.WriteFile(path, []byte("c9s-key-v1:Ym9ndXM="), 0o600))
+	_, err = config.LoadAPIKey(path)
+	assert.ErrorIs(t, err, config.ErrAPIKeyFile)
+}
+
+func TestAIGetAPIKey(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("ANTHROPIC_API_KEY", "sk-env")
+
+	a := config.AI{APIKeyFile: filepath.Join(dir, "claude-key")}
+	key, err := a.GetAPIKey()
+	require.NoError(t, err)
+	assert.Equal(t, "sk-env", key)
+
+	a.APIKey = "sk-plain"
+	key, err = a.GetAPIKey()
+	require.NoError(t, err)
+	assert.Equal(t, "sk-plain", key)
+
+	require.NoError(t, config.SaveAPIKey(a.APIKeyFile, "sk-file"))
+	key, err = a.GetAPIKey()
+	require.NoError(t, err)
+	assert.Equal(t, "sk-file", key)
+}
+
+func TestAIGetAPIKeyCommand(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("credential helper test relies on sh")
+	}
+	count := filepath.Join(t.TempDir(), "count")
+	a := config.AI{
+		APIKey:        "sk-plain",
+		APIKeyCommand: fmt.Sprintf("echo run >> %s; printf 'sk-cmd\\nignored\\n'", count),
+	}
+	for range 2 {
+		key, err := a.GetAPIKey()
+		require.NoError(t, err)
+		assert.Equal(t, "sk-cmd", key)
+	}
+	bb, err := os.ReadFile(count)
+	require.NoError(t, err)
+	assert.Equal(t, 1, strings.Count(string(bb), "run"))
+
+	a.APIKeyCommand = "echo nope >&2; exit 1"
+	_, err = a.GetAPIKey()
+	require.ErrorContains(t, err, "nope")
+
+	a.APIKeyCommand = "true"
+	_, err = a.GetAPIKey()
+	require.ErrorContains(t, err, "returned no key")
+}
+
+func TestAIGetAPIKeyCommandConcurrent(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("credential helper test relies on sh")
+	}
+	count := filepath.Join(t.TempDir(), "count")
+	fast := config.AI{APIKeyCommand: "echo sk-fast"}
+	_, err := fast.GetAPIKey()
+	require.NoError(t, err)
+
+	slow := config.AI{APIKeyCommand: fmt.Sprintf("echo run >> %s; sleep 1; echo sk-slow", count)}
+	var wg sync.WaitGroup
+	for range 3 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			key, err := slow.GetAPIKey()
+			assert.NoError(t, err)
+			assert.Equal(t, "sk-slow", key)
+		}()
+	}
+
+	start := time.Now()
+	key, err := fast.GetAPIKey()
+	require.NoError(t, err)
+	assert.Equal(t, "sk-fast", key)
+	assert.Less(t, time.Since(start), 500*time.Millisecond)
+
+	wg.Wait()
+	bb, err := os.ReadFile(count)
+	require.NoError(t, err)
+	assert.Equal(t, 1, strings.Count(string(bb), "run"))
+}
@@ -0,0 +1,98 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of K9s
+
+package config
+
+import (
+	"errors"
+	"io/fs"
+	"log/slog"
+	"os"
+	"slices"
+
+	"github.com/quentincherifi/c9s/internal/config/data"
+	"github.com/quentincherifi/c9s/internal/config/json"
+	"github.com/quentincherifi/c9s/internal/slogs"
+	"gopkg.in/yaml.v3"
+)
+
+// AIPrompts represents a collection of AI prompts.
+type AIPrompts struct {
+	Prompts map[string]AIPrompt `yaml:"prompts"`
+}
+
+// AIPrompt describes a reusable AI prompt. The prompt text may reference the
+// plugin environment variables, i.e. $NAME or $NAMESPACE.
+type AIPrompt struct {
+	ShortCut    string   `yaml:"shortCut"`
+	Description string   `yaml:"description"`
+	Scopes      []string `yaml:"scopes"`
+	Prompt      string   `yaml:"prompt"`
+}
+
+// NewAIPrompts returns a new prompt collection.
+func NewAIPrompts() AIPrompts {
+	return AIPrompts{
+		Prompts: make(map[string]AIPrompt),
+	}
+}
+
+// Title returns the prompt description or the given name if none.
+func (p AIPrompt) Title(name string) string {
+	if p.Description != "" {
+		return p.Description
+	}
+
+	return name
+}
+
+// Names returns the sorted prompt names.
+func (p AIPrompts) Names() []string {
+	nn := make([]string, 0, len(p.Prompts))
+	for k := range p.Prompts {
+		nn = append(nn, k)
+	}
+	slices.Sort(nn)
+
+	return nn
+}
+
+// Load loads the global prompts and then the context specific ones which
+// take precedence.
+func (p AIPrompts) Load(path string) error {
+	if err := p.LoadPrompts(AppPromptsFile); err != nil {
+		return err
+	}
+	if _, err := os.Stat(path); errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+
+	return p.LoadPrompts(path)
+}
+
+// LoadPrompts loads prompts from a given file.
+func (p AIPrompts) LoadPrompts(path string) error {
+	if _, err := os.Stat(path); errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	bb, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	if err := data.JSONValidator.Validate(json.PromptsSchema, bb); err != nil {
+		slog.Warn("Validation failed. Please update your config and restart.",
+			slogs.Path, path,
+			slogs.Error, err,
+		)
+	}
+
+	var pp AIPrompts
+	if err := yaml.Unmarshal(bb, &pp); err != nil {
+		return err
+	}
+	for k, v := range pp.Prompts {
+		p.Prompts[k] = v
+	}
+
+	return nil
+}
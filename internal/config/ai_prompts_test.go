@@ -0,0 +1,26 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of K9s
+
+package config_test
+
+import (
+	"testing"
+
+	"github.com/quentincherifi/c9s/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAIPromptsLoad(t *testing.T) {
+	p := config.NewAIPrompts()
+	require.NoError(t, p.LoadPrompts("testdata/prompts/prompts.yaml"))
+	assert.Equal(t, []string{"limits", "stuck-rollout"}, p.Names())
+
+	r, ok := p.Prompts["stuck-rollout"]
+	assert.True(t, ok)
+	assert.Equal(t, "Shift-R", r.ShortCut)
+	assert.Equal(t, "Stuck rollout", r.Title("stuck-rollout"))
+	assert.Equal(t, []string{"deploy", "sts"}, r.Scopes)
+	assert.Equal(t, "Check why the rollout of $NAME in $NAMESPACE is stuck.", r.Prompt)
+	assert.Equal(t, "fred", config.AIPrompt{}.Title("fred"))
+}
@@ -0,0 +1,120 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of K9s
+
+package config_test
+
+import (
+	"testing"
+
+	"github.com/quentincherifi/c9s/internal/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAIQuestionFor(t *testing.T) {
+	a := config.AI{
+		Questions: map[string]string{
+			"apps/v1/deployments": "Why is this rollout stuck?",
+			"pods":                "Why is this pod failing?",
+			"v1/services":         "",
+		},
+	}
+
+	uu := map[string]struct {
+		gvr, name string
+		q         string
+		ok        bool
+	}{
+		"gvr": {
+			gvr:  "apps/v1/deployments",
+			name: "deployments",
+			q:    "Why is this rollout stuck?",
+			ok:   true,
+		},
+		"name": {
+			gvr:  "v1/pods",
+			name: "pods",
+			q:    "Why is this pod failing?",
+			ok:   true,
+		},
+		"blank": {
+			gvr:  "v1/services",
+			name: "services",
+		},
+		"none": {
+			gvr:  "v1/nodes",
+			name: "nodes",
+		},
+	}
+
+	for k := range uu {
+		u := uu[k]
+		t.Run(k, func(t *testing.T) {
+			q, ok := a.QuestionFor(u.gvr, u.name)
+			assert.Equal(t, u.ok, ok)
+			assert.Equal(t, u.q, q)
+		})
+	}
+}
+
+func TestAIResolveModel(t *testing.T) {
+	temp := 0.2
+	a := config.AI{
+		Models: config.AIModels{
+			"fast": {Model: "claude-haiku", MaxTokens: 1024},
+			"deep": {Model: "claude-opus", MaxTokens: 8192, Temperature: &temp},
+			"bad":  {MaxTokens: 10},
+		},
+	}
+
+	uu := map[string]struct {
+		name string
+		e    config.AIModel
+	}{
+		"alias": {
+			name: "fast",
+			e:    config.AIModel{Model: "claude-haiku", MaxTokens: 1024},
+		},
+		"temperature": {
+			name: "deep",
+			e:    config.AIModel{Model: "claude-opus", MaxTokens: 8192, Temperature: &temp},
+		},
+		"name": {
+			name: "claude-sonnet",
+			e:    config.AIModel{Model: "claude-sonnet"},
+		},
+		"no-model": {
+			name: "bad",
+			e:    config.AIModel{Model: "bad"},
+		},
+	}
+
+	for k := range uu {
+		u := uu[k]
+		t.Run(k, func(t *testing.T) {
+			assert.Equal(t, u.e, a.ResolveModel(u.name))
+		})
+	}
+	assert.Equal(t, []string{"bad", "deep", "fast"}, a.ModelAliases())
+	assert.True(t, a.IsModelAlias("fast"))
+	assert.False(t, a.IsModelAlias("bad"))
+	assert.False(t, a.IsModelAlias("claude-sonnet"))
+}
+
+func TestAIApplyModel(t *testing.T) {
+	temp := 0.5
+	a := config.NewAI()
+
+	a.ApplyModel(config.AIModel{Model: "claude-haiku"})
+	assert.Equal(t, "claude-haiku", a.GetModel())
+	assert.Equal(t, config.DefaultAIMaxTokens, a.GetMaxTokens())
+	assert.Nil(t, a.Temperature)
+
+	a.ApplyModel(config.AIModel{MaxTokens: 512, Temperature: &temp})
+	assert.Equal(t, "claude-haiku", a.GetModel())
+	assert.Equal(t, 512, a.GetMaxTokens())
+	assert.Equal(t, "claude-haiku max 512 tokens temperature 0.5", a.CurrentModel().String())
+
+	a.ApplyModel(config.AIModel{SystemPrompt: "Answer in French"})
+	assert.Equal(t, "Answer in French", a.SystemPrompt)
+	assert.Equal(t, "claude-haiku max 512 tokens temperature 0.5 with custom instructions", a.CurrentModel().String())
+}
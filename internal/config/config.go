@@ -60,6 +60,16 @@ func (c *Config) ContextHotkeysPath() string {
 	return AppContextHotkeysFile(ct.ClusterName, c.K9s.activeContextName)
 }
 
+// ContextPromptsPath returns a context specific AI prompts file spec.
+func (c *Config) ContextPromptsPath() string {
+	ct, err := c.K9s.ActiveContext()
+	if err != nil {
+		return ""
+	}
+
+	return AppContextPromptsFile(ct.ClusterName, c.K9s.activeContextName)
+}
+
 // ContextAliasesPath returns a context specific aliases file spec.
 func (c *Config) ContextAliasesPath() string {
 	ct, err := c.K9s.ActiveContext()
@@ -276,6 +286,9 @@ func (c *Config) Load(path string, force bool) error {
 		errs = errors.Join(errs, fmt.Errorf("main config.yaml load failed: %w", err))
 	}
 	c.Merge(&cfg)
+	if cfg.K9s != nil && cfg.K9s.AI.APIKey != "" {
+		slog.Warn("Plaintext AI API key found in config. Use `:claude set-key` or apiKeyCommand instead", slogs.Path, path)
+	}
 
 	return errs
 }
@@ -0,0 +1,94 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of K9s
+
+package data
+
+const (
+	// AIShareAll shares manifests, events, table rows and logs.
+	AIShareAll = "all"
+
+	// AIShareNoLogs shares everything but logs.
+	AIShareNoLogs = "noLogs"
+
+	// AIShareNames only shares resource kinds and names.
+	AIShareNames = "names"
+
+	// AIShareNone disables the assistant.
+	AIShareNone = "none"
+)
+
+// AIPolicy tracks what a context may share with the AI assistant.
+// A forced provider only uses the policy key sources, model and TLS settings.
+type AIPolicy struct {
+	Share         string       `yaml:"share,omitempty"`
+	Provider      string       `yaml:"provider,omitempty"`
+	BaseURL       string       `yaml:"baseURL,omitempty"`
+	Model         string       `yaml:"model,omitempty"`
+	APIKeyEnv     string       `yaml:"apiKeyEnv,omitempty"`
+	APIKeyCommand string       `yaml:"apiKeyCommand,omitempty"`
+	TLS           *AIPolicyTLS `yaml:"tls,omitempty"`
+}
+
+// AIPolicyTLS tracks TLS settings used to reach a forced provider.
+type AIPolicyTLS struct {
+	CAFile             string `yaml:"caFile,omitempty"`
+	CertFile           string `yaml:"certFile,omitempty"`
+	KeyFile            string `yaml:"keyFile,omitempty"`
+	InsecureSkipVerify bool   `yaml:"insecureSkipVerify,omitempty"`
+}
+
+// GetShare returns the sharing level, defaulting to sharing all.
+func (p *AIPolicy) GetShare() string {
+	if p == nil || p.Share == "" {
+		return AIShareAll
+	}
+
+	return p.Share
+}
+
+// IsDisabled checks if the assistant is disabled.
+func (p *AIPolicy) IsDisabled() bool {
+	return p.GetShare() == AIShareNone
+}
+
+// AllowsContent checks if resource contents may be shared.
+func (p *AIPolicy) AllowsContent() bool {
+	s := p.GetShare()
+
+	return s == AIShareAll || s == AIShareNoLogs
+}
+
+// AllowsLogs checks if logs may be shared.
+func (p *AIPolicy) AllowsLogs() bool {
+	return p.GetShare() == AIShareAll
+}
+
+// ForcesProvider checks if the policy forces a provider and its model.
+func (p *AIPolicy) ForcesProvider() bool {
+	return p != nil && p.Provider != ""
+}
+
+// IsSet checks if the policy restricts anything.
+func (p *AIPolicy) IsSet() bool {
+	return p.GetShare() != AIShareAll || (p != nil && p.Provider != "")
+}
+
+// String returns a policy summary.
+func (p *AIPolicy) String() string {
+	var s string
+	switch p.GetShare() {
+	case AIShareNoLogs:
+		s = "no logs"
+	case AIShareNames:
+		s = "names only"
+	case AIShareNone:
+		s = "disabled"
+	default:
+		s = "all"
+	}
+	if p != nil && p.Provider != "" {
+		s += " via " + p.Provider
+	}
+
+	return s
+}
@@ -0,0 +1,70 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of K9s
+
+package data_test
+
+import (
+	"testing"
+
+	"github.com/quentincherifi/c9s/internal/config/data"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAIPolicy(t *testing.T) {
+	uu := map[string]struct {
+		p                       *data.AIPolicy
+		disabled, content, logs bool
+		set, forced             bool
+		s                       string
+	}{
+		"none": {
+			content: true,
+			logs:    true,
+			s:       "all",
+		},
+		"all": {
+			p:       &data.AIPolicy{Share: data.AIShareAll},
+			content: true,
+			logs:    true,
+			s:       "all",
+		},
+		"no-logs": {
+			p:       &data.AIPolicy{Share: data.AIShareNoLogs},
+			content: true,
+			set:     true,
+			s:       "no logs",
+		},
+		"names": {
+			p:      &data.AIPolicy{Share: data.AIShareNames, Provider: "openai"},
+			set:    true,
+			forced: true,
+			s:      "names only via openai",
+		},
+		"disabled": {
+			p:        &data.AIPolicy{Share: data.AIShareNone},
+			disabled: true,
+			set:      true,
+			s:        "disabled",
+		},
+		"provider": {
+			p:       &data.AIPolicy{Provider: "openai"},
+			content: true,
+			logs:    true,
+			set:     true,
+			forced:  true,
+			s:       "all via openai",
+		},
+	}
+
+	for k := range uu {
+		u := uu[k]
+		t.Run(k, func(t *testing.T) {
+			assert.Equal(t, u.disabled, u.p.IsDisabled())
+			assert.Equal(t, u.content, u.p.AllowsContent())
+			assert.Equal(t, u.logs, u.p.AllowsLogs())
+			assert.Equal(t, u.set, u.p.IsSet())
+			assert.Equal(t, u.forced, u.p.ForcesProvider())
+			assert.Equal(t, u.s, u.p.String())
+		})
+	}
+}
@@ -20,6 +20,7 @@ type Context struct {
 	View         *View        `yaml:"view"`
 	FeatureGates FeatureGates `yaml:"featureGates"`
 	Proxy        *Proxy       `yaml:"proxy"`
+	AI           *AIPolicy    `yaml:"ai,omitempty"`
 	mx           sync.RWMutex
 }
 
@@ -80,6 +80,15 @@ var (
 
 	// AppHotKeysFile tracks hotkeys config file.
 	AppHotKeysFile string
+
+	// AppPromptsFile tracks AI prompts config file.
+	AppPromptsFile string
+
+	// AppClaudeUsageFile tracks the AI token usage tally file.
+	AppClaudeUsageFile string
+
+	// AppClaudeKeyFile tracks the encrypted AI API key file.
+	AppClaudeKeyFile string
 )
 
 // InitLogLoc initializes K9s logs location.
@@ -151,7 +160,10 @@ func initK9sEnvLocs() error {
 	}
 
 	AppConfigFile = filepath.Join(AppConfigDir, data.MainConfigFile)
+	AppClaudeUsageFile = filepath.Join(AppConfigDir, "claude-usage.json")
+	AppClaudeKeyFile = filepath.Join(AppConfigDir, "claude-key")
 	AppHotKeysFile = filepath.Join(AppConfigDir, "hotkeys.yaml")
+	AppPromptsFile = filepath.Join(AppConfigDir, "prompts.yaml")
 	AppAliasesFile = filepath.Join(AppConfigDir, "aliases.yaml")
 	AppPluginsFile = filepath.Join(AppConfigDir, "plugins.yaml")
 	AppViewsFile = filepath.Join(AppConfigDir, "views.yaml")
@@ -173,6 +185,7 @@ func initXDGLocs() error {
 	}
 
 	AppHotKeysFile = filepath.Join(AppConfigDir, "hotkeys.yaml")
+	AppPromptsFile = filepath.Join(AppConfigDir, "prompts.yaml")
 	AppAliasesFile = filepath.Join(AppConfigDir, "aliases.yaml")
 	AppPluginsFile = filepath.Join(AppConfigDir, "plugins.yaml")
 	AppViewsFile = filepath.Join(AppConfigDir, "views.yaml")
@@ -187,6 +200,16 @@ func initXDGLocs() error {
 		return err
 	}
 
+	AppClaudeUsageFile, err = xdg.StateFile(filepath.Join(AppName, "claude-usage.json"))
+	if err != nil {
+		return err
+	}
+
+	AppClaudeKeyFile, err = xdg.DataFile(filepath.Join(AppName, "claude-key"))
+	if err != nil {
+		return err
+	}
+
 	AppBenchmarksDir, err = xdg.StateFile(filepath.Join(AppName, "benchmarks"))
 	if err != nil {
 		slog.Warn("No benchmarks dir detected",
@@ -230,6 +253,11 @@ func AppContextHotkeysFile(cluster, context string) string {
 	return filepath.Join(AppContextsDir, data.SanitizeContextSubpath(cluster, context), "hotkeys.yaml")
 }
 
+// AppContextPromptsFile generates a valid context specific AI prompts file path.
+func AppContextPromptsFile(cluster, context string) string {
+	return filepath.Join(AppContextsDir, data.SanitizeContextSubpath(cluster, context), "prompts.yaml")
+}
+
 // AppContextConfig generates a valid context config file path.
 func AppContextConfig(cluster, context string) string {
 	return filepath.Join(AppContextDir(cluster, context), data.MainConfigFile)
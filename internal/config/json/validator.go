@@ -35,6 +35,9 @@ const (
 	// HotkeysSchema describes hotkeys schema.
 	HotkeysSchema = "hotkeys.json"
 
+	// PromptsSchema describes AI prompts schema.
+	PromptsSchema = "prompts.json"
+
 	// C9sSchema describes k9s config schema.
 	K9sSchema = "k9s.json"
 
@@ -70,6 +73,9 @@ var (
 	//go:embed schemas/hotkeys.json
 	hotkeysSchema string
 
+	//go:embed schemas/prompts.json
+	promptsSchema string
+
 	//go:embed schemas/skin.json
 	skinSchema string
 )
@@ -92,6 +98,7 @@ func NewValidator() *Validator {
 			PluginSchema:      gojsonschema.NewStringLoader(pluginSchema),
 			PluginMultiSchema: gojsonschema.NewStringLoader(pluginMultiSchema),
 			HotkeysSchema:     gojsonschema.NewStringLoader(hotkeysSchema),
+			PromptsSchema:     gojsonschema.NewStringLoader(promptsSchema),
 			SkinSchema:        gojsonschema.NewStringLoader(skinSchema),
 		},
 	}
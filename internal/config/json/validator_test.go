@@ -166,6 +166,10 @@ func TestValidateContext(t *testing.T) {
 			err: `Additional property fred is not allowed
 Additional property namespaces is not allowed`,
 		},
+		"forced-provider-no-model": {
+			f:   "testdata/context/no-model.yaml",
+			err: `Has a dependency on model`,
+		},
 	}
 
 	v := json.NewValidator()
@@ -238,3 +242,36 @@ columns is required`,
 		})
 	}
 }
+
+func TestValidatePrompts(t *testing.T) {
+	uu := map[string]struct {
+		f   string
+		err string
+	}{
+		"happy": {
+			f: "testdata/prompts/cool.yaml",
+		},
+		"toast": {
+			f: "testdata/prompts/toast.yaml",
+			err: `Additional property shortcut is not allowed
+Invalid type. Expected: array, given: string
+prompt is required`,
+		},
+	}
+
+	v := json.NewValidator()
+	for k := range uu {
+		u := uu[k]
+		t.Run(k, func(t *testing.T) {
+			bb, err := os.ReadFile(u.f)
+			require.NoError(t, err)
+			err = v.Validate(json.PromptsSchema, bb)
+			if u.err == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Equal(t, u.err, err.Error())
+		})
+	}
+}
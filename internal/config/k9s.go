@@ -56,6 +56,7 @@ type K9s struct {
 	manualReadOnly      *bool
 	manualCommand       *string
 	manualScreenDumpDir *string
+	manualAI            *AIModel
 	refreshRateWarned   bool
 	dir                 *data.Dir
 	activeContextName   string
@@ -177,6 +178,11 @@ func (k *K9s) ContextScreenDumpDir() string {
 	return filepath.Join(k.AppScreenDumpDir(), k.contextPath())
 }
 
+// ContextClaudeDir fetch context specific Claude sessions dir.
+func (k *K9s) ContextClaudeDir() string {
+	return filepath.Join(AppContextsDir, k.contextPath(), "claude-sessions")
+}
+
 func (k *K9s) contextPath() string {
 	if k.getActiveConfig() == nil {
 		return "na"
@@ -423,6 +429,62 @@ func (k *K9s) IsReadOnly() bool {
 	return ro
 }
 
+// AIPolicy returns the active context AI policy if any.
+func (k *K9s) AIPolicy() *data.AIPolicy {
+	if cfg := k.getActiveConfig(); cfg != nil && cfg.Context != nil {
+		return cfg.Context.AI
+	}
+
+	return nil
+}
+
+// OverrideAI switches the AI model for the current session only. Settings
+// not set by the model keep their prior override if any.
+func (k *K9s) OverrideAI(m AIModel) {
+	k.mx.Lock()
+	defer k.mx.Unlock()
+
+	if k.manualAI == nil {
+		k.manualAI = new(AIModel)
+	}
+	if m.Model != "" {
+		k.manualAI.Model = m.Model
+	}
+	if m.MaxTokens > 0 {
+		k.manualAI.MaxTokens = m.MaxTokens
+	}
+	if m.Temperature != nil {
+		k.manualAI.Temperature = m.Temperature
+	}
+	if m.SystemPrompt != "" {
+		k.manualAI.SystemPrompt = m.SystemPrompt
+	}
+}
+
+// ResetAI drops the session AI model overrides.
+func (k *K9s) ResetAI() {
+	k.mx.Lock()
+	defer k.mx.Unlock()
+
+	k.manualAI = nil
+}
+
+// ActiveAI returns the AI settings, session overrides applied and the
+// provider forced by the active context policy if any.
+func (k *K9s) ActiveAI() *AI {
+	a := k.AI
+	k.mx.RLock()
+	if k.manualAI != nil {
+		a.ApplyModel(*k.manualAI)
+	}
+	k.mx.RUnlock()
+	if p := k.AIPolicy(); p.ForcesProvider() {
+		a.forceProvider(p)
+	}
+
+	return &a
+}
+
 // Validate the current configuration.
 func (k *K9s) Validate(c client.Connection, contextName, clusterName string) {
 	if k.RefreshRate <= 0 {
@@ -8,6 +8,7 @@ import (
 	"testing"
 
 	"github.com/quentincherifi/c9s/internal/config"
+	"github.com/quentincherifi/c9s/internal/config/data"
 	"github.com/quentincherifi/c9s/internal/config/mock"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -147,3 +148,77 @@ func TestAppScreenDumpDir(t *testing.T) {
 	require.NoError(t, cfg.Load("testdata/configs/k9s.yaml", true))
 	assert.Equal(t, "/tmp/c9s-test/screen-dumps", cfg.K9s.AppScreenDumpDir())
 }
+
+func TestK9sOverrideAI(t *testing.T) {
+	k := config.NewK9s(nil, nil)
+	k.AI.Models = config.AIModels{"fast": {Model: "claude-haiku", MaxTokens: 1024}}
+
+	temp := 0.3
+	k.OverrideAI(k.AI.ResolveModel("fast"))
+	k.OverrideAI(config.AIModel{MaxTokens: 2048})
+	k.OverrideAI(config.AIModel{Temperature: &temp})
+	k.OverrideAI(config.AIModel{SystemPrompt: "Be terse"})
+	a := k.ActiveAI()
+	assert.Equal(t, "claude-haiku", a.GetModel())
+	assert.Equal(t, 2048, a.GetMaxTokens())
+	assert.Equal(t, &temp, a.Temperature)
+	assert.Equal(t, "Be terse", a.SystemPrompt)
+	assert.Nil(t, k.AI.Temperature)
+	assert.Empty(t, k.AI.SystemPrompt)
+	assert.Equal(t, config.DefaultAIModel, k.AI.GetModel())
+	assert.Equal(t, config.DefaultAIMaxTokens, k.AI.GetMaxTokens())
+
+	k.ResetAI()
+	assert.Equal(t, config.DefaultAIModel, k.ActiveAI().GetModel())
+}
+
+func TestK9sActiveAIForcedProvider(t *testing.T) {
+	config.AppConfigDir = "/tmp/c9s-test"
+	cl, ct := "cl-1", "ct-1-1"
+	k := config.NewK9s(
+		mock.NewMockConnection(),
+		mock.NewMockKubeSettings(&genericclioptions.ConfigFlags{
+			ClusterName: &cl,
+			Context:     &ct,
+		}),
+	)
+	k.AI.APIKey, k.AI.APIKeyFile = "sk-ant-global", "/tmp/c9s-test/no-key"
+	k.AI.Headers = map[string]string{"X-Team": "sre"}
+	k.AI.Model = "claude-opus"
+	k.AI.TLS = &config.AITLS{CertFile: "/tmp/global.crt", KeyFile: "/tmp/global.key", InsecureSkipVerify: true}
+	c, err := k.ActivateContext(ct)
+	require.NoError(t, err)
+
+	c.AI = &data.AIPolicy{Provider: config.AIProviderOpenAI, BaseURL: "http://llm.internal/v1"}
+	a := k.ActiveAI()
+	assert.Equal(t, config.AIProviderOpenAI, a.GetProvider())
+	assert.Equal(t, "http://llm.internal/v1", a.BaseURL)
+	assert.Empty(t, a.Headers)
+	assert.Nil(t, a.TLS)
+	assert.Empty(t, a.GetModel())
+	assert.ErrorContains(t, a.Validate(), `forces provider "openai" but sets no model`)
+	t.Setenv("OPENAI_API_KEY", "sk-global")
+	key, err := a.GetAPIKey()
+	require.NoError(t, err)
+	assert.Empty(t, key)
+
+	t.Setenv("LLM_KEY", "sk-llm")
+	c.AI = &data.AIPolicy{
+		Provider:  config.AIProviderOpenAI,
+		Model:     "llama3.1",
+		APIKeyEnv: "LLM_KEY",
+		TLS:       &data.AIPolicyTLS{CAFile: "/tmp/llm-ca.pem"},
+	}
+	a = k.ActiveAI()
+	assert.Equal(t, "llama3.1", a.GetModel())
+	require.NoError(t, a.Validate())
+	assert.Equal(t, &config.AITLS{CAFile: "/tmp/llm-ca.pem"}, a.TLS)
+	assert.NotNil(t, k.AI.TLS)
+	key, err = a.GetAPIKey()
+	require.NoError(t, err)
+	assert.Equal(t, "sk-llm", key)
+
+	key, err = k.AI.GetAPIKey()
+	require.NoError(t, err)
+	assert.Equal(t, "sk-ant-global", key)
+}
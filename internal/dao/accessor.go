@@ -14,6 +14,7 @@ var accessors = Accessors{
 	client.ScnGVR: new(ImageScan),
 	client.SdGVR:  new(ScreenDump),
 	client.BeGVR:  new(Benchmark),
+	client.CsGVR:  new(ClaudeSession),
 	client.PfGVR:  new(PortForward),
 	client.DirGVR: new(Dir),
 
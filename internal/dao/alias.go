@@ -14,6 +14,7 @@ import (
 	"github.com/quentincherifi/c9s/internal/client"
 	"github.com/quentincherifi/c9s/internal/config"
 	"github.com/quentincherifi/c9s/internal/render"
+	"github.com/quentincherifi/c9s/internal/view/cmd"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/sets"
 )
@@ -37,6 +38,11 @@ func NewAlias(f Factory) *Alias {
 	return &a
 }
 
+// ResolveAlias returns the resource matching an alias.
+func (a *Alias) ResolveAlias(alias string) (*client.GVR, bool) {
+	return a.Resolve(cmd.NewInterpreter(alias))
+}
+
 // AliasesFor returns a set of aliases for a given gvr.
 func (a *Alias) AliasesFor(gvr *client.GVR) sets.Set[string] {
 	return a.Aliases.AliasesFor(gvr)
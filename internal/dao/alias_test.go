@@ -28,6 +28,17 @@ func TestAliasList(t *testing.T) {
 	assert.Len(t, oo[0].(render.AliasRes).Aliases, 2)
 }
 
+func TestAliasResolveAlias(t *testing.T) {
+	a := makeAliases()
+
+	gvr, ok := a.ResolveAlias("f")
+	assert.True(t, ok)
+	assert.Equal(t, client.NewGVR("v1/fred"), gvr)
+
+	_, ok = a.ResolveAlias("zorg")
+	assert.False(t, ok)
+}
+
 // ----------------------------------------------------------------------------
 // Helpers...
 
@@ -0,0 +1,68 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of K9s
+
+package dao
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"os"
+	"path/filepath"
+
+	"github.com/quentincherifi/c9s/internal"
+	"github.com/quentincherifi/c9s/internal/render"
+	"github.com/quentincherifi/c9s/internal/slogs"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+var (
+	_ Accessor = (*ClaudeSession)(nil)
+	_ Nuker    = (*ClaudeSession)(nil)
+)
+
+// ClaudeSession represents a persisted Claude conversation.
+type ClaudeSession struct {
+	NonResource
+}
+
+// Delete a ClaudeSession.
+func (*ClaudeSession) Delete(_ context.Context, path string, _ *metav1.DeletionPropagation, _ Grace) error {
+	return os.Remove(path)
+}
+
+// List returns a collection of Claude sessions.
+func (*ClaudeSession) List(ctx context.Context, _ string) ([]runtime.Object, error) {
+	dir, ok := ctx.Value(internal.KeyDir).(string)
+	if !ok {
+		return nil, errors.New("no claude sessions dir found in context")
+	}
+
+	ff, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	oo := make([]runtime.Object, 0, len(ff))
+	for _, f := range ff {
+		s, err := loadSessionRes(f)
+		if err != nil {
+			slog.Warn("Skipping invalid claude session", slogs.Path, f, slogs.Error, err)
+			continue
+		}
+		oo = append(oo, s)
+	}
+
+	return oo, nil
+}
+
+func loadSessionRes(path string) (render.SessionRes, error) {
+	s := render.SessionRes{File: path}
+	bb, err := os.ReadFile(path)
+	if err != nil {
+		return s, err
+	}
+
+	return s, json.Unmarshal(bb, &s)
+}
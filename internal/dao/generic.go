@@ -13,6 +13,7 @@ import (
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/dynamic"
 )
 
@@ -29,7 +30,10 @@ const (
 	NowGrace Grace = 1
 )
 
-var _ Describer = (*Generic)(nil)
+var (
+	_ Describer = (*Generic)(nil)
+	_ Patchable = (*Generic)(nil)
+)
 
 // Generic represents a generic resource.
 type Generic struct {
@@ -139,6 +143,28 @@ func (g *Generic) Delete(ctx context.Context, path string, propagation *metav1.D
 	return dial.Namespace(ns).Delete(ctx, n, opts)
 }
 
+// Patch patches a resource and returns the patched resource.
+func (g *Generic) Patch(ctx context.Context, path string, pt types.PatchType, data []byte, opts metav1.PatchOptions) (runtime.Object, error) {
+	ns, n := client.Namespaced(path)
+	auth, err := g.Client().CanI(ns, g.gvr, n, client.PatchAccess)
+	if err != nil {
+		return nil, err
+	}
+	if !auth {
+		return nil, fmt.Errorf("user is not authorized to patch %s", path)
+	}
+
+	dial, err := g.dynClient()
+	if err != nil {
+		return nil, err
+	}
+	if client.IsClusterScoped(ns) {
+		return dial.Patch(ctx, n, pt, data, opts)
+	}
+
+	return dial.Namespace(ns).Patch(ctx, n, pt, data, opts)
+}
+
 func (g *Generic) dynClient() (dynamic.NamespaceableResourceInterface, error) {
 	dial, err := g.Client().DynDial()
 	if err != nil {
@@ -114,6 +114,24 @@ func (m *Meta) GVK2GVR(gv schema.GroupVersion, kind string) (*client.GVR, bool,
 	return client.NoGVR, false, false
 }
 
+// OwnerFor returns the resource and path of an owner reference for an
+// object living in the given namespace.
+func (m *Meta) OwnerFor(ns string, ref *metav1.OwnerReference) (*client.GVR, string, error) {
+	gv, err := schema.ParseGroupVersion(ref.APIVersion)
+	if err != nil {
+		return nil, "", err
+	}
+	gvr, namespaced, ok := m.GVK2GVR(gv, ref.Kind)
+	if !ok {
+		return nil, "", fmt.Errorf("unsupported GVK: %s/%s", ref.APIVersion, ref.Kind)
+	}
+	if namespaced {
+		return gvr, client.FQN(ns, ref.Name), nil
+	}
+
+	return gvr, ref.Name, nil
+}
+
 // IsNamespaced checks if a given resource is namespaced.
 func (m *Meta) IsNamespaced(gvr *client.GVR) (bool, error) {
 	res, err := m.MetaFor(gvr)
@@ -249,6 +267,14 @@ func loadK9s(m ResourceMetas) {
 		Verbs:        []string{"delete"},
 		Categories:   []string{k9sCat},
 	}
+	m[client.CsGVR] = &metav1.APIResource{
+		Name:         "claudesessions",
+		Kind:         "ClaudeSessions",
+		SingularName: "claudesession",
+		ShortNames:   []string{"cls"},
+		Verbs:        []string{"delete"},
+		Categories:   []string{k9sCat},
+	}
 	m[client.PfGVR] = &metav1.APIResource{
 		Name:         "portforwards",
 		Namespaced:   true,
@@ -14,6 +14,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/informers"
 	restclient "k8s.io/client-go/rest"
 )
@@ -142,6 +143,12 @@ type Switchable interface {
 	Switch(ctx string) error
 }
 
+// Patchable represents a resource that can be patched.
+type Patchable interface {
+	// Patch patches a resource and returns the patched resource.
+	Patch(ctx context.Context, path string, pt types.PatchType, data []byte, opts metav1.PatchOptions) (runtime.Object, error)
+}
+
 // Restartable represents a restartable resource.
 type Restartable interface {
 	// Restart performs a rollout restart.
@@ -122,6 +122,35 @@ func (l *Log) GetContainer() string {
 	return l.logOptions.Container
 }
 
+// GetFilter returns the current log filter if any.
+func (l *Log) GetFilter() string {
+	l.mx.RLock()
+	defer l.mx.RUnlock()
+
+	return l.filter
+}
+
+// Timestamps returns the timestamps of the lines shown, filter applied.
+func (l *Log) Timestamps() []string {
+	ii := l.lines.Items()
+	matches, _, err := l.lines.Filter(0, l.GetFilter(), l.logOptions.ShowTimestamp)
+	if err != nil {
+		return nil
+	}
+	if matches == nil {
+		matches = make([]int, 0, len(ii))
+		for i := range ii {
+			matches = append(matches, i)
+		}
+	}
+	tt := make([]string, 0, len(matches))
+	for _, i := range matches {
+		tt = append(tt, ii[i].GetTimestamp())
+	}
+
+	return tt
+}
+
 // HasDefaultContainer returns true if the pod has a default container, false otherwise.
 func (l *Log) HasDefaultContainer() bool {
 	return l.logOptions.DefaultContainer != ""
@@ -166,6 +166,26 @@ func TestLogBasic(t *testing.T) {
 	assert.Equal(t, ll, v.data)
 }
 
+func TestLogTimestamps(t *testing.T) {
+	m := model.NewLog(client.NewGVR("fred"), makeLogOpts(4), 10*time.Millisecond)
+	m.Init(makeFactory())
+
+	assert.Empty(t, m.Timestamps())
+
+	data := dao.NewLogItems()
+	data.Add(
+		dao.NewLogItemFromString("2024-01-01T10:00:00Z line1"),
+		dao.NewLogItemFromString("2024-01-01T10:00:05Z line2"),
+		dao.NewLogItemFromString("2024-01-01T10:01:00Z line3"),
+	)
+	m.Set(data)
+	assert.Equal(t, []string{"2024-01-01T10:00:00Z", "2024-01-01T10:00:05Z", "2024-01-01T10:01:00Z"}, m.Timestamps())
+
+	m.Filter("line[12]")
+	assert.Equal(t, []string{"2024-01-01T10:00:00Z", "2024-01-01T10:00:05Z"}, m.Timestamps())
+	assert.Equal(t, "line[12]", m.GetFilter())
+}
+
 func TestLogAppend(t *testing.T) {
 	m := model.NewLog(client.NewGVR("fred"), makeLogOpts(4), 5*time.Millisecond)
 	m.Init(makeFactory())
@@ -0,0 +1,46 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of K9s
+
+package model
+
+// MaxQuestionHistory tracks max AI question history.
+const MaxQuestionHistory = 50
+
+// QuestionHistory represents a history of AI questions. Unlike commands,
+// questions keep their case and the oldest ones are dropped once full.
+type QuestionHistory struct {
+	questions []string
+	limit     int
+}
+
+// NewQuestionHistory returns a new instance.
+func NewQuestionHistory(limit int) *QuestionHistory {
+	return &QuestionHistory{limit: limit}
+}
+
+// List returns the questions, oldest first.
+func (h *QuestionHistory) List() []string {
+	return h.questions
+}
+
+// Push adds a new question. A question asked again moves to the top.
+func (h *QuestionHistory) Push(q string) {
+	if q == "" {
+		return
+	}
+	for i, e := range h.questions {
+		if e == q {
+			h.questions = append(h.questions[:i], h.questions[i+1:]...)
+			break
+		}
+	}
+	h.questions = append(h.questions, q)
+	if over := len(h.questions) - h.limit; over > 0 {
+		h.questions = h.questions[over:]
+	}
+}
+
+// Clear clears out the history.
+func (h *QuestionHistory) Clear() {
+	h.questions = nil
+}
@@ -0,0 +1,27 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of K9s
+
+package model_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/quentincherifi/c9s/internal/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQuestionHistoryPush(t *testing.T) {
+	h := model.NewQuestionHistory(3)
+	for i := 1; i < 5; i++ {
+		h.Push(fmt.Sprintf("Why is Pod-%d down?", i))
+	}
+	h.Push("")
+	assert.Equal(t, []string{"Why is Pod-2 down?", "Why is Pod-3 down?", "Why is Pod-4 down?"}, h.List())
+
+	h.Push("Why is Pod-2 down?")
+	assert.Equal(t, []string{"Why is Pod-3 down?", "Why is Pod-4 down?", "Why is Pod-2 down?"}, h.List())
+
+	h.Clear()
+	assert.Empty(t, h.List())
+}
@@ -79,6 +79,10 @@ var Registry = map[*client.GVR]ResourceMeta{
 		DAO:      new(dao.Benchmark),
 		Renderer: new(render.Benchmark),
 	},
+	client.CsGVR: {
+		DAO:      new(dao.ClaudeSession),
+		Renderer: new(render.ClaudeSession),
+	},
 	client.AliGVR: {
 		DAO:      new(dao.Alias),
 		Renderer: new(render.Alias),
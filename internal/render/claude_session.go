@@ -0,0 +1,94 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of K9s
+
+package render
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/quentincherifi/c9s/internal/model1"
+	"github.com/derailed/tcell/v2"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const maxQuestionLen = 80
+
+// ClaudeSession renders persisted Claude conversations to screen.
+type ClaudeSession struct {
+	Base
+}
+
+// ColorerFunc colors a resource row.
+func (ClaudeSession) ColorerFunc() model1.ColorerFunc {
+	return func(string, model1.Header, *model1.RowEvent) tcell.Color {
+		return tcell.ColorAqua
+	}
+}
+
+// Header returns a header row.
+func (ClaudeSession) Header(string) model1.Header {
+	return model1.Header{
+		model1.HeaderColumn{Name: "NAME"},
+		model1.HeaderColumn{Name: "RESOURCE"},
+		model1.HeaderColumn{Name: "QUESTION"},
+		model1.HeaderColumn{Name: "CREATED", Attrs: model1.Attrs{Wide: true}},
+		model1.HeaderColumn{Name: "AGE", Attrs: model1.Attrs{Time: true}},
+	}
+}
+
+// Render renders a K8s resource to screen.
+func (ClaudeSession) Render(o any, _ string, r *model1.Row) error {
+	s, ok := o.(SessionRes)
+	if !ok {
+		return fmt.Errorf("expecting SessionRes, but got %T", o)
+	}
+
+	name := s.Title
+	if name == "" {
+		name = s.ID
+	}
+	res := s.Resource
+	if s.GVR != "" && res != "" {
+		res = s.GVR + ":" + res
+	}
+	q := strings.Join(strings.Fields(s.Question), " ")
+	if rr := []rune(q); len(rr) > maxQuestionLen {
+		q = string(rr[:maxQuestionLen]) + "..."
+	}
+
+	r.ID = s.File
+	r.Fields = model1.Fields{
+		name,
+		res,
+		q,
+		s.CreatedAt.Format(time.RFC3339),
+		timeToAge(s.UpdatedAt),
+	}
+
+	return nil
+}
+
+// SessionRes represents a persisted Claude conversation header.
+type SessionRes struct {
+	File      string    `json:"-"`
+	ID        string    `json:"id"`
+	Title     string    `json:"title"`
+	GVR       string    `json:"gvr"`
+	Resource  string    `json:"path"`
+	Question  string    `json:"question"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// GetObjectKind returns a schema object.
+func (SessionRes) GetObjectKind() schema.ObjectKind {
+	return nil
+}
+
+// DeepCopyObject returns a container copy.
+func (s SessionRes) DeepCopyObject() runtime.Object {
+	return s
+}
@@ -0,0 +1,57 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of K9s
+
+package render_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/quentincherifi/c9s/internal/model1"
+	"github.com/quentincherifi/c9s/internal/render"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClaudeSessionRender(t *testing.T) {
+	uu := map[string]struct {
+		o      render.SessionRes
+		fields model1.Fields
+	}{
+		"titled": {
+			o: render.SessionRes{
+				File:     "fred/s1.json",
+				ID:       "s1",
+				Title:    "crashloop",
+				GVR:      "v1/pods",
+				Resource: "default/p1",
+				Question: "why is\n  it failing?",
+			},
+			fields: model1.Fields{"crashloop", "v1/pods:default/p1", "why is it failing?"},
+		},
+		"untitled": {
+			o: render.SessionRes{
+				File:     "fred/s1.json",
+				ID:       "s1",
+				Question: strings.Repeat("a", 100),
+			},
+			fields: model1.Fields{"s1", "", strings.Repeat("a", 80) + "..."},
+		},
+	}
+
+	for k := range uu {
+		u := uu[k]
+		t.Run(k, func(t *testing.T) {
+			var (
+				s render.ClaudeSession
+				r model1.Row
+			)
+			u.o.CreatedAt, u.o.UpdatedAt = testTime(), testTime()
+
+			require.NoError(t, s.Render(u.o, "", &r))
+			assert.Equal(t, "fred/s1.json", r.ID)
+			assert.Equal(t, u.fields, r.Fields[:3])
+			assert.Len(t, r.Fields, len(s.Header("")))
+		})
+	}
+}
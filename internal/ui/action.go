@@ -26,6 +26,7 @@ type (
 		Shared    bool
 		Plugin    bool
 		HotKey    bool
+		Prompt    bool
 		Dangerous bool
 	}
 
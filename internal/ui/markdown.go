@@ -0,0 +1,188 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of K9s
+
+package ui
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/quentincherifi/c9s/internal/config"
+	"github.com/derailed/tview"
+)
+
+var (
+	mdHeadingRX = regexp.MustCompile(`^(#{1,6})\s+(.*?)\s*#*\s*$`)
+	mdBulletRX  = regexp.MustCompile(`^(\s*)[-*+]\s+(.*)$`)
+	mdNumberRX  = regexp.MustCompile(`^(\s*)(\d+[.)])\s+(.*)$`)
+	mdQuoteRX   = regexp.MustCompile(`^\s*>\s?(.*)$`)
+	mdRuleRX    = regexp.MustCompile(`^\s*(-\s*){3,}$|^\s*(\*\s*){3,}$|^\s*(_\s*){3,}$`)
+	mdFenceRX   = regexp.MustCompile("^\\s*(```+|~~~+)\\s*([\\w+#.-]*)")
+
+	// mdInlineRX matches code spans, bold, italic and links.
+	mdInlineRX = regexp.MustCompile("`([^`]+)`|\\*\\*([^*]+)\\*\\*|__([^_]+)__|\\*([^*\\s][^*]*)\\*|\\[([^\\]]+)\\]\\(([^)\\s]+)\\)")
+)
+
+const mdReset = "[-:-:-]"
+
+// MarkdownStyle tracks the colors used to render Markdown.
+type MarkdownStyle struct {
+	HeadingColor config.Color
+	BulletColor  config.Color
+	CodeColor    config.Color
+	FrameColor   config.Color
+	LinkColor    config.Color
+	QuoteColor   config.Color
+}
+
+// NewMarkdownStyle returns a Markdown style matching the active skin.
+func NewMarkdownStyle(s *config.Styles) MarkdownStyle {
+	return MarkdownStyle{
+		HeadingColor: s.K9s.Help.SectionColor,
+		BulletColor:  s.K9s.Help.KeyColor,
+		CodeColor:    s.Views().Yaml.ValueColor,
+		FrameColor:   s.Frame().Border.FgColor,
+		LinkColor:    s.Frame().Title.HighlightColor,
+		QuoteColor:   s.Frame().Title.CounterColor,
+	}
+}
+
+// CodeBlock represents a fenced code block.
+type CodeBlock struct {
+	Lang string
+	Code string
+}
+
+// Label returns a short description of the block.
+func (c CodeBlock) Label() string {
+	l, _, _ := strings.Cut(strings.TrimSpace(c.Code), "\n")
+	if c.Lang == "" {
+		return l
+	}
+
+	return c.Lang + ": " + l
+}
+
+// Markdown renders Markdown as tview markup. All text is escaped so brackets
+// are never mistaken for color tags. Code blocks are numbered across renders
+// so they may be referenced later on.
+type Markdown struct {
+	style  MarkdownStyle
+	blocks []CodeBlock
+}
+
+// NewMarkdown returns a new renderer.
+func NewMarkdown(s MarkdownStyle) *Markdown {
+	return &Markdown{style: s}
+}
+
+// CodeBlocks returns the code blocks rendered so far.
+func (m *Markdown) CodeBlocks() []CodeBlock {
+	return m.blocks
+}
+
+// Render converts Markdown text to tview markup. An unterminated code block,
+// i.e. while an answer is streamed, is rendered up to the end of the text.
+func (m *Markdown) Render(md string) string {
+	var (
+		sb    strings.Builder
+		code  *CodeBlock
+		fence string
+		lines []string
+	)
+	for _, l := range strings.Split(md, "\n") {
+		if code != nil {
+			if f := mdFenceRX.FindStringSubmatch(l); f != nil && strings.HasPrefix(f[1], fence) && f[2] == "" {
+				code.Code = strings.Join(lines, "\n")
+				m.closeCode(&sb, code)
+				code, lines = nil, nil
+				continue
+			}
+			lines = append(lines, l)
+			continue
+		}
+		if f := mdFenceRX.FindStringSubmatch(l); f != nil {
+			code, fence = &CodeBlock{Lang: f[2]}, f[1]
+			m.blocks = append(m.blocks, *code)
+			fmt.Fprintf(&sb, "[%s::]  ┌─ [%d[] %s%s\n", m.style.FrameColor, len(m.blocks), tview.Escape(code.Lang), mdReset)
+			continue
+		}
+		sb.WriteString(m.line(l))
+		sb.WriteString("\n")
+	}
+	if code != nil {
+		code.Code = strings.Join(lines, "\n")
+		m.closeCode(&sb, code)
+	}
+
+	return strings.TrimSuffix(sb.String(), "\n")
+}
+
+func (m *Markdown) closeCode(sb *strings.Builder, code *CodeBlock) {
+	m.blocks[len(m.blocks)-1] = *code
+	if code.Code != "" {
+		for _, l := range strings.Split(code.Code, "\n") {
+			fmt.Fprintf(sb, "[%s::]  │ [%s::]%s%s\n", m.style.FrameColor, m.style.CodeColor, tview.Escape(l), mdReset)
+		}
+	}
+	fmt.Fprintf(sb, "[%s::]  └─%s\n", m.style.FrameColor, mdReset)
+}
+
+func (m *Markdown) line(l string) string {
+	switch {
+	case mdRuleRX.MatchString(l):
+		return fmt.Sprintf("[%s::]%s%s", m.style.FrameColor, strings.Repeat("─", 40), mdReset)
+	case mdHeadingRX.MatchString(l):
+		mm := mdHeadingRX.FindStringSubmatch(l)
+		base := fmt.Sprintf("[%s::b]", m.style.HeadingColor)
+		return base + m.inline(mm[2], base) + mdReset
+	case mdBulletRX.MatchString(l):
+		mm := mdBulletRX.FindStringSubmatch(l)
+		return fmt.Sprintf("%s[%s::b]•%s %s", mm[1], m.style.BulletColor, mdReset, m.inline(mm[2], mdReset))
+	case mdNumberRX.MatchString(l):
+		mm := mdNumberRX.FindStringSubmatch(l)
+		return fmt.Sprintf("%s[%s::b]%s%s %s", mm[1], m.style.BulletColor, mm[2], mdReset, m.inline(mm[3], mdReset))
+	case mdQuoteRX.MatchString(l):
+		mm := mdQuoteRX.FindStringSubmatch(l)
+		base := fmt.Sprintf("[%s::]", m.style.QuoteColor)
+		return base + "│ " + m.inline(mm[1], base) + mdReset
+	default:
+		return m.inline(l, mdReset)
+	}
+}
+
+// inline renders code spans, emphasis and links, restoring the base style
+// after each of them.
+func (m *Markdown) inline(s, base string) string {
+	var (
+		sb   strings.Builder
+		last int
+	)
+	for _, mm := range mdInlineRX.FindAllStringSubmatchIndex(s, -1) {
+		sb.WriteString(tview.Escape(s[last:mm[0]]))
+		last = mm[1]
+		group := func(i int) string {
+			return tview.Escape(s[mm[2*i]:mm[2*i+1]])
+		}
+		switch {
+		case mm[2] >= 0:
+			fmt.Fprintf(&sb, "[%s::]%s", m.style.CodeColor, group(1))
+		case mm[4] >= 0:
+			fmt.Fprintf(&sb, "[::b]%s", group(2))
+		case mm[6] >= 0:
+			fmt.Fprintf(&sb, "[::b]%s", group(3))
+		case mm[8] >= 0:
+			fmt.Fprintf(&sb, "[::u]%s", group(4))
+		default:
+			fmt.Fprintf(&sb, "[%s::u]%s[%s::-] (%s)", m.style.LinkColor, group(5), m.style.QuoteColor, group(6))
+		}
+		sb.WriteString(mdReset)
+		if base != mdReset {
+			sb.WriteString(base)
+		}
+	}
+	sb.WriteString(tview.Escape(s[last:]))
+
+	return sb.String()
+}
@@ -0,0 +1,89 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of K9s
+
+package ui_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/quentincherifi/c9s/internal/ui"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMarkdownRender(t *testing.T) {
+	st := ui.MarkdownStyle{
+		HeadingColor: "#000001",
+		BulletColor:  "#000002",
+		CodeColor:    "#000003",
+		FrameColor:   "#000004",
+		LinkColor:    "#000005",
+		QuoteColor:   "#000006",
+	}
+
+	uu := map[string]struct {
+		md, e string
+	}{
+		"plain": {
+			md: "All good",
+			e:  "All good",
+		},
+		"escape": {
+			md: "Check [red] and [::b] markers",
+			e:  "Check [red[] and [::b[] markers",
+		},
+		"heading": {
+			md: "## Root `cause` ##",
+			e:  "[#000001::b]Root [#000003::]cause[-:-:-][#000001::b][-:-:-]",
+		},
+		"bullets": {
+			md: "- **bad** image\n  1. pull *again*",
+			e:  "[#000002::b]•[-:-:-] [::b]bad[-:-:-] image\n  [#000002::b]1.[-:-:-] pull [::u]again[-:-:-]",
+		},
+		"rule": {
+			md: "* * *",
+			e:  "[#000004::]" + strings.Repeat("─", 40) + "[-:-:-]",
+		},
+		"snake-case": {
+			md: "set max_surge and max_unavailable",
+			e:  "set max_surge and max_unavailable",
+		},
+		"quote-link": {
+			md: "> see [docs](https://k8s.io)",
+			e:  "[#000006::]│ see [#000005::u]docs[#000006::-] (https://k8s.io)[-:-:-][#000006::][-:-:-]",
+		},
+		"code": {
+			md: "Run:\n```sh\nkubectl get [po]\n```\nDone",
+			e: "Run:\n[#000004::]  ┌─ [1[] sh[-:-:-]\n" +
+				"[#000004::]  │ [#000003::]kubectl get [po[][-:-:-]\n" +
+				"[#000004::]  └─[-:-:-]\nDone",
+		},
+		"unterminated": {
+			md: "```\npods",
+			e: "[#000004::]  ┌─ [1[] [-:-:-]\n" +
+				"[#000004::]  │ [#000003::]pods[-:-:-]\n" +
+				"[#000004::]  └─[-:-:-]",
+		},
+	}
+
+	for k := range uu {
+		u := uu[k]
+		t.Run(k, func(t *testing.T) {
+			assert.Equal(t, u.e, ui.NewMarkdown(st).Render(u.md))
+		})
+	}
+}
+
+func TestMarkdownCodeBlocks(t *testing.T) {
+	m := ui.NewMarkdown(ui.MarkdownStyle{})
+	m.Render("```yaml\nkind: Pod\nmetadata: {}\n```")
+	m.Render("Then\n~~~\n:pods -n kube-system\n~~~")
+
+	bb := m.CodeBlocks()
+	assert.Equal(t, []ui.CodeBlock{
+		{Lang: "yaml", Code: "kind: Pod\nmetadata: {}"},
+		{Code: ":pods -n kube-system"},
+	}, bb)
+	assert.Equal(t, "yaml: kind: Pod", bb[0].Label())
+	assert.Equal(t, ":pods -n kube-system", bb[1].Label())
+}
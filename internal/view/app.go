@@ -52,6 +52,7 @@ type App struct {
 	clusterModel  *model.ClusterInfo
 	cmdHistory    *model.History
 	filterHistory *model.History
+	claudeHistory *model.QuestionHistory
 	conRetry      int32
 	showHeader    bool
 	showLogo      bool
@@ -64,6 +65,7 @@ func NewApp(cfg *config.Config) *App {
 		App:           ui.NewApp(cfg, cfg.K9s.ActiveContextName()),
 		cmdHistory:    model.NewHistory(model.MaxHistory),
 		filterHistory: model.NewHistory(model.MaxHistory),
+		claudeHistory: model.NewQuestionHistory(model.MaxQuestionHistory),
 		Content:       NewPageStack(),
 	}
 	a.ReloadStyles()
@@ -148,6 +150,7 @@ func (*App) stopImgScanner() {
 func (a *App) clearHistory() {
 	a.cmdHistory.Clear()
 	a.filterHistory.Clear()
+	a.claudeHistory.Clear()
 }
 
 func (a *App) initImgScanner(version string) {
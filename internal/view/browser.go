@@ -487,6 +487,10 @@ func (b *Browser) deleteCmd(evt *tcell.EventKey) *tcell.EventKey {
 	return nil
 }
 
+func (b *Browser) selectedTarget() (*client.GVR, string) {
+	return b.GVR(), b.GetSelectedItem()
+}
+
 func (b *Browser) describeCmd(evt *tcell.EventKey) *tcell.EventKey {
 	path := b.GetSelectedItem()
 	if path == "" {
@@ -640,6 +644,7 @@ func (b *Browser) refreshActions() {
 	if !dao.IsK9sMeta(b.meta) {
 		aa.Add(ui.KeyY, ui.NewKeyAction(yamlAction, b.viewCmd, true))
 		aa.Add(ui.KeyD, ui.NewKeyAction("Describe", b.describeCmd, true))
+		aa.Bulk(askClaudeKey(b.app, b.selectedTarget))
 	}
 	for _, f := range b.bindKeysFn {
 		f(aa)
@@ -654,6 +659,10 @@ func (b *Browser) refreshActions() {
 		slog.Warn("Hotkeys load failed", slogs.Error, err)
 		b.app.Logo().Warn("HotKeys load failed!")
 	}
+	if err := promptActions(b, b.Actions()); err != nil {
+		slog.Warn("Prompts load failed", slogs.Error, err)
+		b.app.Logo().Warn("Prompts load failed!")
+	}
 	b.app.Menu().HydrateMenu(b.Hints())
 }
 
@@ -5,13 +5,21 @@ package view
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log/slog"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/quentincherifi/c9s/internal"
 	"github.com/quentincherifi/c9s/internal/ai"
+	"github.com/quentincherifi/c9s/internal/client"
 	"github.com/quentincherifi/c9s/internal/config"
 	"github.com/quentincherifi/c9s/internal/model"
+	"github.com/quentincherifi/c9s/internal/model1"
+	"github.com/quentincherifi/c9s/internal/slogs"
 	"github.com/quentincherifi/c9s/internal/ui"
 	"github.com/quentincherifi/c9s/internal/view/cmd"
 	"github.com/derailed/tcell/v2"
@@ -34,7 +42,28 @@ type Claude struct {
 	chatHistory *tview.TextView
 	contextInfo *tview.TextView
 	messages    []ai.Message
+	session     *ai.Session
 	k8sContext  *ai.K8sContext
+	tools       *ai.Toolbox
+	selGVR      *client.GVR
+	selPath     string
+	enrich      sync.Once
+	runbooks    *ai.RunbookIndex
+	indexOnce   sync.Once
+	grounded    []ai.Excerpt
+	stats       string
+	today       ai.Usage
+	cancelFn    context.CancelFunc
+	partial     []streamPart
+	codeBlocks  []ui.CodeBlock
+	cursor      int
+	editIdx     int
+	proposals   []*proposal
+	logLines    []string
+	jumpFn      LineJumpFunc
+	autoAsk     bool
+	draft       string
+	mx          sync.Mutex
 }
 
 // NewClaude returns a new Claude view instance.
@@ -47,26 +76,64 @@ func NewClaude(app *App, question string) *Claude {
 		chatHistory: tview.NewTextView(),
 		contextInfo: tview.NewTextView(),
 		messages:    make([]ai.Message, 0),
+		cursor:      noCursor,
+		editIdx:     noCursor,
 	}
 
 	c.buildK8sContext()
+	c.session = c.newSession()
 
 	if question != "" {
 		c.messages = append(c.messages, ai.Message{
 			Role:    "user",
 			Content: question,
+			Pinned:  true,
 		})
+		c.autoAsk = true
 	}
 
 	return c
 }
 
+// NewClaudeSession returns a Claude view resuming a persisted conversation.
+func NewClaudeSession(app *App, s *ai.Session) *Claude {
+	c := NewClaude(app, "")
+	c.session, c.messages = s, append(c.messages, s.Messages...)
+	c.selGVR, c.selPath = nil, ""
+	// The conversation is about its own resource, not the sessions browser.
+	c.k8sContext.ResourceType, c.k8sContext.Rows, c.k8sContext.RowsSource = "", "", ""
+	c.k8sContext.SelectedResource = s.Path
+	if s.GVR != "" && s.Path != "" {
+		c.target(client.NewGVR(s.GVR), s.Path)
+	}
+
+	return c
+}
+
+// NewClaudeFor returns a Claude view focused on a given resource.
+func NewClaudeFor(app *App, gvr *client.GVR, path, question string) *Claude {
+	c := NewClaude(app, question)
+	c.target(gvr, path)
+	c.session = c.newSession()
+
+	return c
+}
+
+func (c *Claude) target(gvr *client.GVR, path string) {
+	c.selGVR, c.selPath = gvr, path
+	c.k8sContext.ResourceType = gvr.R()
+	c.k8sContext.SelectedResource = path
+}
+
 func (*Claude) SetCommand(*cmd.Interpreter)            {}
 func (*Claude) SetFilter(string, bool)                 {}
 func (*Claude) SetLabelSelector(labels.Selector, bool) {}
 
 // Init initializes the view.
 func (c *Claude) Init(_ context.Context) error {
+	if c.app.Config.K9s.AIPolicy().IsDisabled() {
+		return ai.ErrPolicyDisabled
+	}
 	c.SetDirection(tview.FlexRow)
 	c.SetBorder(true)
 	c.SetTitle(fmt.Sprintf(claudeTitleFmt, claudeTitle))
@@ -82,6 +149,7 @@ func (c *Claude) Init(_ context.Context) error {
 
 	// Chat history section
 	c.chatHistory.SetDynamicColors(true)
+	c.chatHistory.SetRegions(true)
 	c.chatHistory.SetScrollable(true)
 	c.chatHistory.SetWrap(true)
 	c.chatHistory.SetBorder(true)
@@ -100,10 +168,15 @@ func (c *Claude) Init(_ context.Context) error {
 
 	c.app.Prompt().SetModel(c.cmdBuff)
 	c.cmdBuff.AddListener(c)
+	c.cmdBuff.SetSuggestionFn(c.suggestQuestion())
 
-	// If we have an initial question, send it
-	if len(c.messages) > 0 {
-		go c.sendMessage()
+	c.updateChatDisplay()
+
+	// Only send the initial question. A resumed conversation ending with an
+	// unanswered question, i.e. cancelled or failed, waits for the user.
+	if c.autoAsk {
+		c.autoAsk = false
+		c.ask()
 	}
 
 	return nil
@@ -124,6 +197,9 @@ func (c *Claude) buildK8sContext() {
 
 	c.k8sContext.Namespace = c.app.Config.ActiveNamespace()
 
+	c.tools = c.toolbox()
+	c.k8sContext.ToolsEnabled = c.tools != nil
+
 	// Get current view info
 	if top := c.app.Content.Top(); top != nil {
 		c.k8sContext.ResourceType = top.Name()
@@ -144,128 +220,645 @@ func (c *Claude) extractSelectedResource() {
 		if tbl := rv.GetTable(); tbl != nil {
 			if sel := tbl.GetSelectedItem(); sel != "" {
 				c.k8sContext.SelectedResource = sel
+				c.selGVR, c.selPath = rv.GVR(), sel
+			}
+			// Internal views, i.e. saved sessions, are not shared as rows.
+			if ai.IsK8sResource(rv.GVR()) {
+				c.extractRows(tbl)
 			}
 		}
 	}
 }
 
+// extractRows shares the marked rows or, failing that, the visible rows of
+// a table so questions may compare resources.
+func (c *Claude) extractRows(tbl *Table) {
+	if !c.app.Config.K9s.AIPolicy().AllowsContent() {
+		return
+	}
+	data := tbl.GetFilteredData()
+	if data == nil || data.RowCount() == 0 {
+		return
+	}
+	var marked, visible [][]string
+	data.RowsRange(func(_ int, re model1.RowEvent) bool {
+		visible = append(visible, re.Row.Fields)
+		if tbl.IsMarked(re.Row.ID) {
+			marked = append(marked, re.Row.Fields)
+		}
+		return true
+	})
+
+	rows, maxRows := visible, c.app.Config.K9s.AI.GetMaxRows()
+	c.k8sContext.RowsSource = fmt.Sprintf("%d of %d visible rows", min(len(rows), maxRows), len(rows))
+	if len(marked) > 0 {
+		rows = marked
+		c.k8sContext.RowsSource = fmt.Sprintf("%d marked rows", min(len(rows), maxRows))
+	}
+	c.k8sContext.Rows = ai.TableRows(data.ColumnNames(true), rows, maxRows)
+}
+
+func (c *Claude) newSession() *ai.Session {
+	k := c.snapshot()
+	s := ai.NewSession(k.ClusterName, k.ContextName)
+	if c.selGVR != nil {
+		s.GVR, s.Path = c.selGVR.String(), c.selPath
+	}
+
+	return s
+}
+
+// saveSession persists the conversation so it may be resumed later.
+func (c *Claude) saveSession() {
+	if len(c.messages) == 0 {
+		return
+	}
+	c.session.SetMessages(c.messages)
+	if err := c.session.Save(c.app.Config.K9s.ContextClaudeDir()); err != nil {
+		slog.Warn("Unable to save Claude session", slogs.Error, err)
+	}
+}
+
+// loadResourceContext pulls the selected resource manifest and events into
+// the AI context, trimmed to the configured budgets.
+func (c *Claude) loadResourceContext() {
+	if c.selGVR == nil || c.app.factory == nil || !ai.IsK8sResource(c.selGVR) || !c.app.Config.K9s.AIPolicy().AllowsContent() {
+		return
+	}
+	k := c.snapshot()
+	info, err := k.LoadResource(c.app.factory, c.selGVR, c.selPath, &c.app.Config.K9s.AI)
+	if err != nil {
+		slog.Warn("Unable to gather AI resource context",
+			slogs.GVR, c.selGVR,
+			slogs.FQN, c.selPath,
+			slogs.Error, err,
+		)
+		return
+	}
+	if err := k.LoadTree(context.Background(), c.app.factory, c.selGVR, c.selPath, &c.app.Config.K9s.AI); err != nil {
+		slog.Warn("Unable to gather AI dependency tree",
+			slogs.GVR, c.selGVR,
+			slogs.FQN, c.selPath,
+			slogs.Error, err,
+		)
+	}
+
+	c.mx.Lock()
+	c.k8sContext = &k
+	c.mx.Unlock()
+
+	yamlSize, events := len(k.ResourceYAML), len(info.Events)
+	stats := fmt.Sprintf("%dB yaml, %d events", yamlSize, events)
+	if k.Tree != "" {
+		stats += fmt.Sprintf(", %d tree nodes", strings.Count(k.Tree, "\n")+1)
+	}
+	c.app.QueueUpdateDraw(func() {
+		c.stats = stats
+		c.updateContextDisplay()
+	})
+}
+
+// snapshot returns a copy of the gathered context. The resource context is
+// loaded in the background, so the context is only ever read through a copy.
+func (c *Claude) snapshot() ai.K8sContext {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+
+	return *c.k8sContext
+}
+
+// prepareContext returns the context shared along with a conversation, as
+// allowed by the active context policy. Each request gets its own copy.
+func (c *Claude) prepareContext(msgs []ai.Message, cfg *config.AI) *ai.K8sContext {
+	c.enrich.Do(c.loadResourceContext)
+	k := c.snapshot()
+	k.Runbooks = c.groundRunbooks(msgs)
+	k.Restrict(c.app.Config.K9s.AIPolicy())
+	k.Instructions = cfg.SystemPrompt
+
+	return &k
+}
+
+// groundRunbooks returns the runbook excerpts best matching the last question.
+// Runbooks are indexed the first time they are needed.
+func (c *Claude) groundRunbooks(msgs []ai.Message) []ai.Excerpt {
+	cfg := c.app.Config.K9s.AI.Runbooks
+	dirs := cfg.GetDirs()
+	if len(dirs) == 0 {
+		return nil
+	}
+	c.indexOnce.Do(func() {
+		idx, err := ai.LoadRunbooks(dirs)
+		if err != nil {
+			slog.Warn("Unable to index some runbooks", slogs.Error, err)
+		}
+		c.runbooks = idx
+	})
+	var question string
+	for i := len(msgs) - 1; i >= 0; i-- {
+		if msgs[i].Role == "user" && len(msgs[i].Blocks) == 0 {
+			question = msgs[i].Content
+			break
+		}
+	}
+
+	return c.runbooks.Search(question, cfg.GetMaxExcerpts(), cfg.GetMaxSize())
+}
+
 func (c *Claude) updateContextDisplay() {
-	var sb strings.Builder
+	var (
+		sb strings.Builder
+		k  = c.snapshot()
+	)
 	sb.WriteString("[yellow]Cluster:[white] ")
-	if c.k8sContext.ClusterName != "" {
-		sb.WriteString(c.k8sContext.ClusterName)
+	if k.ClusterName != "" {
+		sb.WriteString(k.ClusterName)
 	} else {
 		sb.WriteString("N/A")
 	}
 	sb.WriteString("  [yellow]Context:[white] ")
-	if c.k8sContext.ContextName != "" {
-		sb.WriteString(c.k8sContext.ContextName)
+	if k.ContextName != "" {
+		sb.WriteString(k.ContextName)
 	} else {
 		sb.WriteString("N/A")
 	}
 	sb.WriteString("  [yellow]Namespace:[white] ")
-	if c.k8sContext.Namespace != "" {
-		sb.WriteString(c.k8sContext.Namespace)
+	if k.Namespace != "" {
+		sb.WriteString(k.Namespace)
 	} else {
 		sb.WriteString("all")
 	}
-	if c.k8sContext.ResourceType != "" {
+	if k.ResourceType != "" {
 		sb.WriteString("  [yellow]View:[white] ")
-		sb.WriteString(c.k8sContext.ResourceType)
+		sb.WriteString(k.ResourceType)
+	}
+	sb.WriteString("  [yellow]Model:[white] ")
+	sb.WriteString(tview.Escape(c.app.Config.K9s.ActiveAI().GetModel()))
+	if p := c.app.Config.K9s.AIPolicy(); p.IsSet() {
+		sb.WriteString("  [yellow]Policy:[orange] ")
+		sb.WriteString(p.String())
+		sb.WriteString("[white]")
 	}
-	if c.k8sContext.SelectedResource != "" {
+	if k.Rows != "" {
+		sb.WriteString("  [yellow]Rows:[white] ")
+		sb.WriteString(k.RowsSource)
+	}
+	if k.SelectedResource != "" {
 		sb.WriteString("\n[yellow]Selected:[white] ")
-		sb.WriteString(c.k8sContext.SelectedResource)
+		sb.WriteString(k.SelectedResource)
+		if c.stats != "" {
+			sb.WriteString(" [gray](" + c.stats + ")[white]")
+		}
+	}
+	if len(c.grounded) > 0 {
+		sb.WriteString("\n[yellow]Runbooks:[white] ")
+		sb.WriteString(tview.Escape(strings.Join(ai.ExcerptFiles(c.grounded), ", ")))
+	}
+	if u := ai.TotalUsage(c.messages); u.Total() > 0 || c.today.Total() > 0 {
+		sb.WriteString("\n[yellow]Tokens:[white] ")
+		sb.WriteString(strconv.Itoa(u.Total()))
+		sb.WriteString("  [yellow]Today:[white] ")
+		sb.WriteString(strconv.Itoa(c.today.Total()))
+		if b := c.app.Config.K9s.AI.Budget; b != nil && b.DailyTokens > 0 {
+			sb.WriteString("/" + strconv.Itoa(b.DailyTokens))
+		}
+	}
+	if comp := c.session.Compaction; comp != nil {
+		sb.WriteString("  [yellow]Compacted:[white] ")
+		sb.WriteString(strconv.Itoa(comp.Count) + "x")
 	}
 
 	c.contextInfo.SetText(sb.String())
 }
 
 func (c *Claude) updateChatDisplay() {
-	var sb strings.Builder
-
-	for _, msg := range c.messages {
-		switch msg.Role {
-		case "user":
-			sb.WriteString("[aqua::b]You:[white:-:-] ")
-			sb.WriteString(msg.Content)
-			sb.WriteString("\n\n")
-		case "assistant":
-			sb.WriteString("[green::b]Claude:[white:-:-] ")
-			sb.WriteString(msg.Content)
-			sb.WriteString("\n\n")
+	var (
+		sb strings.Builder
+		md = ui.NewMarkdown(ui.NewMarkdownStyle(c.app.Styles))
+	)
+	comp := c.session.Compaction
+	for i, msg := range c.messages {
+		if comp != nil && i == comp.Upto {
+			fmt.Fprintf(&sb, "[gray::d]── %d earlier messages summarized for Claude, ~%d down to ~%d tokens ──[-::-]\n\n", comp.Upto, comp.Before, comp.After)
+		}
+		fmt.Fprintf(&sb, `["%s"]`, msgRegion(i))
+		if msg.Pinned {
+			sb.WriteString(pinMarker)
 		}
+		renderMessage(&sb, &msg, md)
+		sb.WriteString(`[""]`)
 	}
+	if c.isStreaming() {
+		sb.WriteString("[green::b]Claude:[white:-:-] ")
+		if text := c.renderPartial(md); text != "" {
+			sb.WriteString(text)
+		} else {
+			sb.WriteString("[gray]Thinking...[white]")
+		}
+		sb.WriteString("\n")
+	}
+	c.codeBlocks = md.CodeBlocks()
 
 	c.chatHistory.SetText(sb.String())
-	c.chatHistory.ScrollToEnd()
+	if c.cursor == noCursor {
+		c.chatHistory.Highlight()
+		c.chatHistory.ScrollToEnd()
+		return
+	}
+	c.chatHistory.Highlight(msgRegion(c.cursor))
+	c.chatHistory.ScrollToHighlight()
 }
 
-func (c *Claude) sendMessage() {
-	apiKey := c.app.Config.K9s.AI.GetAPIKey()
-	if apiKey == "" {
-		c.app.QueueUpdateDraw(func() {
-			c.messages = append(c.messages, ai.Message{
-				Role:    "assistant",
-				Content: "[red]Error: API key not configured. Use ':claude set-key <your-api-key>' to set it.",
-			})
-			c.updateChatDisplay()
-		})
+// errNotice returns a notice reporting an error in the chat.
+func errNotice(format string, args ...any) ai.Message {
+	return ai.Message{
+		Role:    "assistant",
+		Content: fmt.Sprintf(format, args...),
+		Notice:  true,
+	}
+}
+
+// renderAnswer renders an answer as Markdown. Notices are plain text.
+func renderAnswer(msg *ai.Message, md *ui.Markdown) string {
+	if msg.Notice {
+		return "[red]" + tview.Escape(msg.Content) + "[white]"
+	}
+
+	return md.Render(msg.Content)
+}
+
+func renderMessage(sb *strings.Builder, msg *ai.Message, md *ui.Markdown) {
+	if len(msg.Blocks) > 0 {
+		renderBlocks(sb, msg, md)
 		return
 	}
+	switch msg.Role {
+	case "user":
+		sb.WriteString("[aqua::b]You:[white:-:-] ")
+		sb.WriteString(tview.Escape(msg.Content))
+		sb.WriteString("\n\n")
+	case "assistant":
+		sb.WriteString("[green::b]Claude:[white:-:-] ")
+		sb.WriteString(renderAnswer(msg, md))
+		sb.WriteString("\n")
+		if msg.Usage != nil {
+			sb.WriteString(usageLine(*msg.Usage))
+		}
+		sb.WriteString("\n")
+	}
+}
 
-	// Show loading indicator
-	c.app.QueueUpdateDraw(func() {
-		c.chatHistory.SetText(c.chatHistory.GetText(false) + "[gray]Thinking...[white]\n")
-	})
+func usageLine(u ai.Usage) string {
+	return fmt.Sprintf("[gray::d]  %s[-::-]\n", u)
+}
 
-	client := ai.NewClient(
-		apiKey,
-		c.app.Config.K9s.AI.GetModel(),
-		c.app.Config.K9s.AI.GetMaxTokens(),
-	)
+func renderBlocks(sb *strings.Builder, msg *ai.Message, md *ui.Markdown) {
+	if text := msg.Text(); text != "" && msg.Role == "assistant" {
+		sb.WriteString("[green::b]Claude:[white:-:-] ")
+		sb.WriteString(md.Render(text))
+		sb.WriteString("\n")
+	}
+	for _, b := range msg.Blocks {
+		switch b.Type {
+		case ai.BlockToolUse:
+			if b.Name == ai.ProposeActionTool {
+				sb.WriteString(proposalLine(string(b.Input)))
+			} else {
+				sb.WriteString(toolUseLine(b.Name, string(b.Input)))
+			}
+		case ai.BlockToolResult:
+			switch {
+			case b.IsError:
+				sb.WriteString(toolErrLine(b.Content))
+			case strings.HasPrefix(b.Content, ai.ProposalRecorded):
+				sb.WriteString(proposalDiff(b.Content))
+			default:
+				sb.WriteString(toolResultLine(b.Content))
+			}
+		}
+	}
+	if msg.Role == "user" {
+		sb.WriteString("\n")
+	}
+}
+
+func toolUseLine(name, input string) string {
+	return fmt.Sprintf("[orange::b]Tool:[orange::-] %s %s[white:-:-]\n", name, tview.Escape(input))
+}
+
+func toolResultLine(res string) string {
+	return fmt.Sprintf("[gray]  -> %d bytes read[white]\n", len(res))
+}
+
+func toolErrLine(err string) string {
+	return fmt.Sprintf("[red]  -> %s[white]\n", tview.Escape(err))
+}
+
+func (c *Claude) auditTool(call ai.ToolCall) {
+	line := toolUseLine(call.Name, call.Input)
+	if call.Name == ai.ProposeActionTool {
+		line = proposalLine(call.Input)
+	}
+	switch {
+	case call.Err != nil:
+		line += toolErrLine(call.Err.Error())
+	case call.Name == ai.ProposeActionTool:
+		line += proposalDiff(call.Result)
+	default:
+		line += toolResultLine(call.Result)
+	}
+	c.appendAudit("\n" + line)
+}
+
+func (c *Claude) isStreaming() bool {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+
+	return c.cancelFn != nil
+}
+
+// streamPart tracks a chunk of the answer being streamed. Text parts are
+// Markdown while tool audit parts are already marked up.
+type streamPart struct {
+	text   string
+	markup bool
+}
+
+// renderPartial renders the answer streamed so far.
+func (c *Claude) renderPartial(md *ui.Markdown) string {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+
+	var sb strings.Builder
+	for _, p := range c.partial {
+		if p.markup {
+			sb.WriteString(p.text)
+			continue
+		}
+		sb.WriteString(md.Render(p.text))
+	}
+
+	return sb.String()
+}
 
-	systemPrompt, err := ai.BuildSystemPrompt(c.k8sContext)
+// appendPartial adds streamed answer text.
+func (c *Claude) appendPartial(text string) {
+	c.appendPart(text, false)
+}
+
+// appendAudit adds a tool audit line.
+func (c *Claude) appendAudit(line string) {
+	c.appendPart(line, true)
+}
+
+func (c *Claude) appendPart(text string, markup bool) {
+	c.mx.Lock()
+	if n := len(c.partial); n > 0 && c.partial[n-1].markup == markup {
+		c.partial[n-1].text += text
+	} else {
+		c.partial = append(c.partial, streamPart{text: text, markup: markup})
+	}
+	c.mx.Unlock()
+
+	c.app.QueueUpdateDraw(c.updateChatDisplay)
+}
+
+// ask snapshots the conversation and streams Claude's answer in the background.
+func (c *Claude) ask() {
+	c.mx.Lock()
+	if c.cancelFn != nil {
+		c.mx.Unlock()
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	c.cancelFn = cancel
+	c.partial = nil
+	c.mx.Unlock()
+
+	msgs := make([]ai.Message, len(c.messages))
+	copy(msgs, c.messages)
+	c.updateChatDisplay()
+
+	go c.sendMessage(ctx, msgs, c.session.Compaction)
+}
+
+// endStream clears the in-flight state.
+func (c *Claude) endStream() {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+
+	if c.cancelFn != nil {
+		c.cancelFn()
+		c.cancelFn = nil
+	}
+	c.partial = nil
+}
+
+func (c *Claude) sendMessage(ctx context.Context, msgs []ai.Message, prev *ai.Compaction) {
+	cfg := c.app.Config.K9s.ActiveAI()
+	k := c.prepareContext(msgs, cfg)
+
+	client, err := configClient(cfg)
 	if err != nil {
 		c.app.QueueUpdateDraw(func() {
-			c.messages = append(c.messages, ai.Message{
-				Role:    "assistant",
-				Content: fmt.Sprintf("[red]Error building prompt: %v", err),
-			})
+			c.endStream()
+			c.messages = append(c.messages, errNotice("Error: %s", err))
 			c.updateChatDisplay()
 		})
 		return
 	}
 
-	resp, err := client.Send(systemPrompt, c.messages)
+	client.SetRetryFunc(c.retryNotice)
+	if l := client.Ledger(); l != nil {
+		if warn, err := l.Check(time.Now()); err == nil && warn != "" {
+			c.app.QueueUpdateDraw(func() {
+				c.app.Flash().Warn(warn)
+			})
+		}
+	}
+
+	systemPrompt, err := ai.BuildSystemPrompt(k)
 	if err != nil {
 		c.app.QueueUpdateDraw(func() {
-			c.messages = append(c.messages, ai.Message{
-				Role:    "assistant",
-				Content: fmt.Sprintf("[red]Error: %v", err),
-			})
+			c.endStream()
+			c.messages = append(c.messages, errNotice("Error building prompt: %s", err))
 			c.updateChatDisplay()
 		})
 		return
 	}
 
+	req := c.compact(ctx, client, systemPrompt, msgs, prev)
+	turn, err := client.Converse(ctx, systemPrompt, req, c.tools, c.appendPartial, c.auditTool)
+	var today *ai.Usage
+	if l := client.Ledger(); l != nil {
+		if t, e := l.Tally(); e == nil {
+			u := t.Day(time.Now())
+			today = &u
+		}
+	}
 	c.app.QueueUpdateDraw(func() {
-		c.messages = append(c.messages, ai.Message{
-			Role:    "assistant",
-			Content: resp.GetText(),
-		})
+		c.endStream()
+		c.messages, c.grounded = append(c.messages, turn...), k.Runbooks
+		if today != nil {
+			c.today = *today
+		}
+		switch {
+		case errors.Is(err, context.Canceled):
+			c.app.Flash().Warn("Claude response cancelled")
+		case errors.Is(err, ai.ErrBudgetExceeded):
+			c.app.Flash().Err(err)
+			c.messages = append(c.messages, errNotice("%s", ai.Describe(err)))
+		case err != nil:
+			c.messages = append(c.messages, errNotice("Error: %s", ai.Describe(err)))
+		}
+		c.saveSession()
+		c.updateContextDisplay()
 		c.updateChatDisplay()
 	})
 }
 
+// compact summarizes older turns once the conversation grows past the
+// configured threshold and returns the messages to send. The chat still
+// displays the whole conversation.
+func (c *Claude) compact(ctx context.Context, client *ai.Client, system string, msgs []ai.Message, prev *ai.Compaction) []ai.Message {
+	cfg := c.app.Config.K9s.AI.Compaction
+	threshold := cfg.GetThreshold()
+	if threshold <= 0 || ai.EstimateRequest(system, prev.Apply(msgs)) < threshold {
+		return prev.Apply(msgs)
+	}
+
+	c.app.QueueUpdateDraw(func() {
+		c.app.Flash().Info("Compacting conversation...")
+	})
+	cc, err := client.Compact(ctx, prev, system, msgs, cfg.GetKeep())
+	if err != nil {
+		if !errors.Is(err, ai.ErrNothingToCompact) {
+			slog.Warn("Conversation compaction failed", slogs.Error, err)
+			c.app.QueueUpdateDraw(func() {
+				c.app.Flash().Warnf("Conversation compaction failed: %s", ai.Describe(err))
+			})
+		}
+		return prev.Apply(msgs)
+	}
+	c.app.QueueUpdateDraw(func() {
+		c.session.Compaction = cc
+		c.app.Flash().Infof("Conversation compacted: %d earlier messages summarized, ~%d down to ~%d tokens", cc.Upto, cc.Before, cc.After)
+	})
+
+	return cc.Apply(msgs)
+}
+
+// retryNotice reports a throttled or failed request about to be retried.
+func (c *Claude) retryNotice(attempt, maxRetries int, wait time.Duration, err error) {
+	msg := fmt.Sprintf("Claude request failed, retrying in %s (%d/%d): %v", wait.Round(time.Second), attempt, maxRetries, err)
+	c.app.QueueUpdateDraw(func() {
+		c.app.Flash().Warn(msg)
+	})
+}
+
+func (c *Claude) newClient(apiKey string) (*ai.Client, error) {
+	return ai.NewConfigClient(c.app.Config.K9s.ActiveAI(), apiKey)
+}
+
+// configClient resolves the API key and returns a client for the given
+// settings. Key helpers may take a while, so it is never called from the
+// event loop.
+func configClient(cfg *config.AI) (*ai.Client, error) {
+	apiKey, err := cfg.GetAPIKey()
+	if err != nil {
+		return nil, err
+	}
+	if apiKey == "" && cfg.RequiresAPIKey() {
+		return nil, errors.New("API key not configured. Use ':claude set-key <your-api-key>' to set it")
+	}
+
+	return ai.NewConfigClient(cfg, apiKey)
+}
+
+// preview shows the exact request the next question would be sent along
+// with, once redacted.
+func (c *Claude) preview(msgs []ai.Message, comp *ai.Compaction) {
+	k := c.prepareContext(msgs, c.app.Config.K9s.ActiveAI())
+
+	raw, n, err := c.buildPreview(k, comp.Apply(msgs))
+	c.app.QueueUpdateDraw(func() {
+		if err != nil {
+			c.app.Flash().Err(err)
+			return
+		}
+		details := NewDetails(c.app, "Preview", fmt.Sprintf("%d values redacted", n), contentTXT, true).Update(tview.Escape(raw))
+		if err := c.app.inject(details, false); err != nil {
+			c.app.Flash().Err(err)
+		}
+	})
+}
+
+func (c *Claude) buildPreview(k *ai.K8sContext, msgs []ai.Message) (string, int, error) {
+	apiKey, err := c.app.Config.K9s.ActiveAI().GetAPIKey()
+	if err != nil {
+		return "", 0, err
+	}
+	client, err := c.newClient(apiKey)
+	if err != nil {
+		return "", 0, err
+	}
+	systemPrompt, err := ai.BuildSystemPrompt(k)
+	if err != nil {
+		return "", 0, err
+	}
+
+	return client.Preview(systemPrompt, msgs, c.tools)
+}
+
+// toolbox returns the cluster tools Claude may call or nil when no cluster
+// is reachable. Remediation proposals are only offered when writes are allowed.
+func (c *Claude) toolbox() *ai.Toolbox {
+	if c.app.factory == nil || c.app.Conn() == nil || !c.app.Conn().ConnectionOK() {
+		return nil
+	}
+	var r ai.Resolver
+	if c.app.command != nil && c.app.command.alias != nil {
+		r = c.app.command.alias
+	}
+
+	tb := ai.NewK8sToolbox(c.app.factory, r)
+	if !c.app.Config.IsReadOnly() {
+		ai.RegisterActions(tb, c.app.factory, r, c.propose)
+	}
+	tb.Restrict(c.app.Config.K9s.AIPolicy())
+
+	return tb
+}
+
 func (c *Claude) bindKeys() {
 	c.actions.Bulk(ui.KeyMap{
 		tcell.KeyEscape: ui.NewKeyAction("Back", c.backCmd, true),
 		ui.KeyQ:         ui.NewKeyAction("Back", c.backCmd, false),
 		tcell.KeyEnter:  ui.NewKeyAction("Send", c.sendCmd, true),
 		tcell.KeyCtrlL:  ui.NewKeyAction("Clear", c.clearCmd, true),
+		tcell.KeyCtrlX:  ui.NewKeyAction("Cancel", c.cancelCmd, true),
+		ui.KeyP:         ui.NewKeyAction("Preview", c.previewCmd, true),
+		ui.KeyC:         ui.NewKeyAction("Copy Code", c.copyCodeCmd, true),
+		ui.KeyR:         ui.NewKeyAction("Run Code", c.runCodeCmd, true),
+		ui.KeyN:         ui.NewKeyAction("Next Message", c.nextMsgCmd, true),
+		ui.KeyShiftN:    ui.NewKeyAction("Prev Message", c.prevMsgCmd, true),
+		ui.KeyY:         ui.NewKeyAction("Copy Message", c.copyMsgCmd, true),
+		ui.KeyE:         ui.NewKeyAction("Edit Question", c.editCmd, true),
+		ui.KeyShiftP:    ui.NewKeyAction("Pin Message", c.pinCmd, true),
+		ui.KeyM:         ui.NewKeyAction("Model", c.modelCmd, true),
+		tcell.KeyCtrlS:  ui.NewKeyAction("Save", c.saveCmd, false),
 		ui.KeyColon:     ui.NewSharedKeyAction("Prompt", c.activateCmd, false),
 	})
+	if c.jumpFn != nil {
+		c.actions.Add(ui.KeyJ, ui.NewKeyAction("Jump To Log", c.jumpCmd, true))
+	}
+	if !c.app.Config.IsReadOnly() {
+		c.actions.Add(ui.KeyA, ui.NewKeyActionWithOpts("Apply", c.applyCmd,
+			ui.ActionOpts{
+				Visible:   true,
+				Dangerous: true,
+			}))
+	}
 }
 
 func (c *Claude) keyboard(evt *tcell.EventKey) *tcell.EventKey {
@@ -278,10 +871,14 @@ func (c *Claude) keyboard(evt *tcell.EventKey) *tcell.EventKey {
 
 func (c *Claude) backCmd(evt *tcell.EventKey) *tcell.EventKey {
 	if c.cmdBuff.InCmdMode() {
-		c.cmdBuff.SetActive(false)
 		c.cmdBuff.Reset()
 		return nil
 	}
+	if c.cursor != noCursor {
+		c.cursor = noCursor
+		c.updateChatDisplay()
+		return nil
+	}
 	return c.app.PrevCmd(evt)
 }
 
@@ -291,26 +888,87 @@ func (c *Claude) sendCmd(evt *tcell.EventKey) *tcell.EventKey {
 	}
 
 	question := c.cmdBuff.GetText()
-	c.cmdBuff.SetActive(false)
 	c.cmdBuff.Reset()
 
+	c.submit(question)
+
+	return nil
+}
+
+func (c *Claude) submit(question string) {
+	if c.isStreaming() {
+		c.app.Flash().Warn("Claude is still responding. Press Ctrl-X to cancel")
+		return
+	}
+	if c.editIdx != noCursor {
+		c.rewind(c.editIdx)
+		c.editIdx = noCursor
+	}
+	c.app.claudeHistory.Push(question)
 	c.messages = append(c.messages, ai.Message{
 		Role:    "user",
 		Content: question,
+		Pinned:  len(c.messages) == 0,
 	})
-	c.updateChatDisplay()
+	c.ask()
+}
+
+func (c *Claude) cancelCmd(*tcell.EventKey) *tcell.EventKey {
+	c.cancel()
+
+	return nil
+}
+
+// cancel aborts the in-flight request if any.
+func (c *Claude) cancel() {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+
+	if c.cancelFn != nil {
+		c.cancelFn()
+	}
+}
 
-	go c.sendMessage()
+func (c *Claude) previewCmd(*tcell.EventKey) *tcell.EventKey {
+	if c.isStreaming() {
+		c.app.Flash().Warn("Claude is still responding. Press Ctrl-X to cancel")
+		return nil
+	}
+	msgs := make([]ai.Message, len(c.messages))
+	copy(msgs, c.messages)
+	go c.preview(msgs, c.session.Compaction)
 
 	return nil
 }
 
 func (c *Claude) clearCmd(*tcell.EventKey) *tcell.EventKey {
+	if c.isStreaming() {
+		c.app.Flash().Warn("Claude is still responding. Press Ctrl-X to cancel")
+		return nil
+	}
 	c.messages = make([]ai.Message, 0)
+	c.cursor, c.editIdx = noCursor, noCursor
+	c.session = c.newSession()
+	c.mx.Lock()
+	c.proposals = nil
+	c.mx.Unlock()
 	c.chatHistory.SetText("")
 	return nil
 }
 
+// Focus hands the focus to the prompt, loaded with the drafted question if
+// any, so it may be edited before it is sent.
+func (c *Claude) Focus(delegate func(p tview.Primitive)) {
+	if c.draft == "" {
+		c.Flex.Focus(delegate)
+		return
+	}
+	draft := c.draft
+	c.draft = ""
+	c.app.ResetPrompt(c.cmdBuff)
+	c.cmdBuff.SetText(draft, "", true)
+}
+
 func (c *Claude) activateCmd(evt *tcell.EventKey) *tcell.EventKey {
 	if c.app.InCmdMode() {
 		return evt
@@ -323,23 +981,22 @@ func (c *Claude) activateCmd(evt *tcell.EventKey) *tcell.EventKey {
 func (*Claude) BufferChanged(_, _ string) {}
 
 // BufferCompleted indicates input was accepted.
-func (c *Claude) BufferCompleted(text, _ string) {
-	if text == "" {
-		return
-	}
+func (*Claude) BufferCompleted(_, _ string) {}
 
-	c.messages = append(c.messages, ai.Message{
-		Role:    "user",
-		Content: text,
-	})
-	c.updateChatDisplay()
-
-	go c.sendMessage()
-}
-
-// BufferActive indicates the buff activity changed.
+// BufferActive indicates the buff activity changed. Questions are sent once
+// the prompt is closed with text left in, since cancelling clears it first.
 func (c *Claude) BufferActive(state bool, k model.BufferKind) {
 	c.app.BufferActive(state, k)
+	if state {
+		return
+	}
+	question := strings.TrimSpace(c.cmdBuff.GetText())
+	c.cmdBuff.ClearText(false)
+	if question == "" {
+		c.editIdx = noCursor
+		return
+	}
+	c.submit(question)
 }
 
 // InCmdMode checks if prompt is active.
@@ -363,8 +1020,9 @@ func (*Claude) Name() string { return claudeTitle }
 // Start starts the view updater.
 func (*Claude) Start() {}
 
-// Stop terminates the updater.
+// Stop terminates the updater and cancels the in-flight request if any.
 func (c *Claude) Stop() {
+	c.cancel()
 	c.app.Styles.RemoveListener(c)
 }
 
@@ -0,0 +1,141 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of K9s
+
+package view
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/quentincherifi/c9s/internal/ai"
+	"github.com/quentincherifi/c9s/internal/ui/dialog"
+	"github.com/derailed/tcell/v2"
+	"github.com/derailed/tview"
+)
+
+const applyTimeout = 30 * time.Second
+
+// proposal tracks a remediation proposed by Claude.
+type proposal struct {
+	action  *ai.Action
+	applied bool
+}
+
+// propose records a remediation proposal. It is called from the tool runner.
+func (c *Claude) propose(a *ai.Action) {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+
+	c.proposals = append(c.proposals, &proposal{action: a})
+}
+
+func (c *Claude) pendingProposals() []*proposal {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+
+	pp := make([]*proposal, 0, len(c.proposals))
+	for _, p := range c.proposals {
+		if !p.applied {
+			pp = append(pp, p)
+		}
+	}
+
+	return pp
+}
+
+func (c *Claude) applyCmd(*tcell.EventKey) *tcell.EventKey {
+	if c.app.Config.IsReadOnly() {
+		c.app.Flash().Warn("Remediations are disabled in read-only mode")
+		return nil
+	}
+	if c.isStreaming() {
+		c.app.Flash().Warn("Claude is still responding. Press Ctrl-X to cancel")
+		return nil
+	}
+
+	pp := c.pendingProposals()
+	switch len(pp) {
+	case 0:
+		c.app.Flash().Info("No pending proposals to apply")
+	case 1:
+		c.confirmApply(pp[0])
+	default:
+		oo := make([]string, 0, len(pp))
+		for _, p := range pp {
+			oo = append(oo, p.action.String())
+		}
+		d := c.app.Styles.Dialog()
+		dialog.ShowSelection(&d, c.app.Content.Pages, "Proposals", oo, func(i int) {
+			if i >= 0 && i < len(pp) {
+				c.confirmApply(pp[i])
+			}
+		})
+	}
+
+	return nil
+}
+
+func (c *Claude) confirmApply(p *proposal) {
+	msg := fmt.Sprintf("%s?\n%s", p.action.String(), p.action.Reason)
+	d := c.app.Styles.Dialog()
+	dialog.ShowConfirm(&d, c.app.Content.Pages, "Apply Proposal", msg, func() {
+		go c.apply(p)
+	}, func() {})
+}
+
+func (c *Claude) apply(p *proposal) {
+	ctx, cancel := context.WithTimeout(context.Background(), applyTimeout)
+	defer cancel()
+
+	err := ai.ApplyAction(ctx, c.app.factory, p.action)
+	c.app.QueueUpdateDraw(func() {
+		if err != nil {
+			c.app.Flash().Errf("Apply failed: %s", err)
+			return
+		}
+		c.mx.Lock()
+		p.applied = true
+		c.mx.Unlock()
+		c.app.Flash().Infof("Applied: %s", p.action.String())
+	})
+}
+
+func proposalLine(input string) string {
+	var a ai.Action
+	if err := json.Unmarshal([]byte(input), &a); err != nil {
+		return toolUseLine(ai.ProposeActionTool, input)
+	}
+
+	return fmt.Sprintf("[fuchsia::b]Proposal:[fuchsia::-] %s[white:-:-] %s\n",
+		tview.Escape(a.String()),
+		tview.Escape(a.Reason),
+	)
+}
+
+func proposalDiff(res string) string {
+	diff, ok := strings.CutPrefix(res, ai.ProposalRecorded+"\n")
+	if !ok {
+		return toolResultLine(res)
+	}
+
+	var sb strings.Builder
+	for _, l := range strings.Split(strings.TrimRight(diff, "\n"), "\n") {
+		color := "gray"
+		switch {
+		case strings.HasPrefix(l, "+++"), strings.HasPrefix(l, "---"):
+			color = "white"
+		case strings.HasPrefix(l, "@@"):
+			color = "aqua"
+		case strings.HasPrefix(l, "+"):
+			color = "green"
+		case strings.HasPrefix(l, "-"):
+			color = "red"
+		}
+		sb.WriteString("  [" + color + "]" + tview.Escape(l) + "[white]\n")
+	}
+
+	return sb.String()
+}
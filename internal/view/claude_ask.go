@@ -0,0 +1,68 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of K9s
+
+package view
+
+import (
+	"fmt"
+
+	"github.com/quentincherifi/c9s/internal/client"
+	"github.com/quentincherifi/c9s/internal/dao"
+	"github.com/quentincherifi/c9s/internal/ui"
+	"github.com/derailed/tcell/v2"
+)
+
+const (
+	askClaudeAction = "Ask Claude"
+	defaultAskFmt   = "Explain this %s and diagnose any issue you can spot."
+)
+
+// targetFunc returns the resource a view is focused on.
+type targetFunc func() (*client.GVR, string)
+
+// askClaudeKey binds the ask Claude action to a view.
+func askClaudeKey(app *App, fn targetFunc) ui.KeyMap {
+	return ui.KeyMap{
+		tcell.KeyCtrlO: ui.NewKeyAction(askClaudeAction, askClaudeCmd(app, fn), true),
+	}
+}
+
+func askClaudeCmd(app *App, fn targetFunc) ui.ActionHandler {
+	return func(evt *tcell.EventKey) *tcell.EventKey {
+		gvr, path := fn()
+		if gvr == nil || path == "" {
+			return evt
+		}
+		askClaude(app, gvr, path)
+
+		return nil
+	}
+}
+
+// askClaude opens the Claude view with the canned question for the resource
+// loaded in the prompt, or sent right away when so configured.
+func askClaude(app *App, gvr *client.GVR, path string) {
+	q := askQuestion(app, gvr)
+	var v *Claude
+	if app.Config.K9s.AI.AutoAsk {
+		v = NewClaudeFor(app, gvr, path, q)
+	} else {
+		v = NewClaudeFor(app, gvr, path, "")
+		v.draft = q
+	}
+	if err := app.inject(v, false); err != nil {
+		app.Flash().Err(err)
+	}
+}
+
+func askQuestion(app *App, gvr *client.GVR) string {
+	if q, ok := app.Config.K9s.AI.QuestionFor(gvr.String(), gvr.R()); ok {
+		return q
+	}
+	kind := gvr.R()
+	if m, err := dao.MetaAccess.MetaFor(gvr); err == nil && m.SingularName != "" {
+		kind = m.SingularName
+	}
+
+	return fmt.Sprintf(defaultAskFmt, kind)
+}
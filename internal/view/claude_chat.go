@@ -0,0 +1,229 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of K9s
+
+package view
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/quentincherifi/c9s/internal/ai"
+	"github.com/quentincherifi/c9s/internal/config/data"
+	"github.com/quentincherifi/c9s/internal/model"
+	"github.com/derailed/tcell/v2"
+)
+
+const (
+	// noCursor indicates no message is selected.
+	noCursor = -1
+
+	// pinMarker flags messages kept verbatim when compacting.
+	pinMarker = "[yellow::b]Pinned[-::-] "
+)
+
+// msgRegion returns the chat region id of a message.
+func msgRegion(i int) string {
+	return fmt.Sprintf("msg-%d", i)
+}
+
+// chatStops lists the messages the cursor may land on, i.e. questions and
+// answers. Tool calls and results are skipped.
+func chatStops(mm []ai.Message) []int {
+	ii := make([]int, 0, len(mm))
+	for i := range mm {
+		if mm[i].Notice {
+			continue
+		}
+		if len(mm[i].Blocks) == 0 || (mm[i].Role == "assistant" && mm[i].Text() != "") {
+			ii = append(ii, i)
+		}
+	}
+
+	return ii
+}
+
+// stepCursor returns the stop before or after the cursor. Without a cursor
+// the last stop is picked.
+func stepCursor(stops []int, cursor int, forward bool) int {
+	if len(stops) == 0 {
+		return noCursor
+	}
+	if cursor == noCursor {
+		return stops[len(stops)-1]
+	}
+	if forward {
+		if i := slices.IndexFunc(stops, func(s int) bool { return s > cursor }); i >= 0 {
+			return stops[i]
+		}
+		return cursor
+	}
+	for i := len(stops) - 1; i >= 0; i-- {
+		if stops[i] < cursor {
+			return stops[i]
+		}
+	}
+
+	return cursor
+}
+
+// isQuestion checks if a message was typed in by the user.
+func isQuestion(m *ai.Message) bool {
+	return m.Role == "user" && len(m.Blocks) == 0
+}
+
+func (c *Claude) prevMsgCmd(*tcell.EventKey) *tcell.EventKey {
+	c.moveCursor(false)
+
+	return nil
+}
+
+func (c *Claude) nextMsgCmd(*tcell.EventKey) *tcell.EventKey {
+	c.moveCursor(true)
+
+	return nil
+}
+
+func (c *Claude) moveCursor(forward bool) {
+	cursor := stepCursor(chatStops(c.messages), c.cursor, forward)
+	if cursor == noCursor {
+		c.app.Flash().Info("No messages yet")
+		return
+	}
+	c.cursor = cursor
+	c.updateChatDisplay()
+}
+
+// selectedMessage returns the message under the cursor or the last answer
+// when no message is selected.
+func (c *Claude) selectedMessage() (*ai.Message, bool) {
+	if c.cursor != noCursor && c.cursor < len(c.messages) {
+		return &c.messages[c.cursor], true
+	}
+	for i := len(c.messages) - 1; i >= 0; i-- {
+		if m := &c.messages[i]; m.Role == "assistant" && !m.Notice && m.Text() != "" {
+			return m, true
+		}
+	}
+
+	return nil, false
+}
+
+func (c *Claude) copyMsgCmd(*tcell.EventKey) *tcell.EventKey {
+	m, ok := c.selectedMessage()
+	if !ok {
+		c.app.Flash().Info("No answers yet")
+		return nil
+	}
+	if err := clipboardWrite(m.Text()); err != nil {
+		c.app.Flash().Err(err)
+		return nil
+	}
+	c.app.Flash().Info("Message copied to clipboard...")
+
+	return nil
+}
+
+// editCmd loads the selected question in the prompt. Once sent, the
+// conversation is replayed from that question on.
+func (c *Claude) editCmd(*tcell.EventKey) *tcell.EventKey {
+	if c.isStreaming() {
+		c.app.Flash().Warn("Claude is still responding. Press Ctrl-X to cancel")
+		return nil
+	}
+	if c.cursor == noCursor || c.cursor >= len(c.messages) || !isQuestion(&c.messages[c.cursor]) {
+		c.app.Flash().Info("Select one of your questions to edit it")
+		return nil
+	}
+	c.editIdx = c.cursor
+	c.app.ResetPrompt(c.cmdBuff)
+	c.cmdBuff.ClearSuggestions()
+	c.cmdBuff.SetText(c.messages[c.editIdx].Content, "", true)
+
+	return nil
+}
+
+// pinCmd toggles whether the selected message is quoted verbatim once the
+// conversation is compacted.
+func (c *Claude) pinCmd(*tcell.EventKey) *tcell.EventKey {
+	if c.isStreaming() {
+		c.app.Flash().Warn("Claude is still responding. Press Ctrl-X to cancel")
+		return nil
+	}
+	if c.cursor == noCursor || c.cursor >= len(c.messages) {
+		c.app.Flash().Info("Select a message to pin it")
+		return nil
+	}
+	m := &c.messages[c.cursor]
+	m.Pinned = !m.Pinned
+	c.saveSession()
+	c.updateChatDisplay()
+	if m.Pinned {
+		c.app.Flash().Info("Message pinned")
+	} else {
+		c.app.Flash().Info("Message unpinned")
+	}
+
+	return nil
+}
+
+// rewind drops the messages from the edited question on. A compaction
+// summarizing dropped messages no longer applies.
+func (c *Claude) rewind(i int) {
+	c.messages = c.messages[:i]
+	if comp := c.session.Compaction; comp != nil && comp.Upto > i {
+		c.session.Compaction = nil
+	}
+	c.cursor = noCursor
+}
+
+func (c *Claude) saveCmd(*tcell.EventKey) *tcell.EventKey {
+	if len(c.messages) == 0 {
+		c.app.Flash().Info("Nothing to save yet")
+		return nil
+	}
+	c.session.SetMessages(c.messages)
+	path, err := exportSession(c.app.Config.K9s.ContextScreenDumpDir(), c.session)
+	if err != nil {
+		c.app.Flash().Err(err)
+		return nil
+	}
+	c.app.Flash().Infof("Transcript %s saved successfully!", path)
+
+	return nil
+}
+
+// exportSession writes a conversation transcript as Markdown.
+func exportSession(dir string, s *ai.Session) (string, error) {
+	if err := data.EnsureFullPath(dir, data.DefaultDirMod); err != nil {
+		return "", err
+	}
+	path := filepath.Join(dir, "claude-"+s.ID+".md")
+	if err := os.WriteFile(path, []byte(s.Markdown()), data.DefaultFileMod); err != nil {
+		return "", err
+	}
+
+	return path, nil
+}
+
+// suggestQuestion recalls previous questions, most recent first. Prefixes
+// match regardless of case but questions are recalled as asked.
+func (c *Claude) suggestQuestion() model.SuggestionFunc {
+	return func(s string) (entries sort.StringSlice) {
+		hh := slices.Clone(c.app.claudeHistory.List())
+		slices.Reverse(hh)
+		if s == "" {
+			return hh
+		}
+
+		for _, h := range hh {
+			if len(h) > len(s) && strings.EqualFold(h[:len(s)], s) {
+				entries = append(entries, h[len(s):])
+			}
+		}
+		return
+	}
+}
@@ -0,0 +1,94 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of K9s
+
+package view
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/quentincherifi/c9s/internal/ai"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChatStops(t *testing.T) {
+	mm := []ai.Message{
+		{Role: "user", Content: "why?"},
+		{Role: "assistant", Blocks: []ai.ContentBlock{
+			{Type: ai.BlockToolUse, ID: "t1", Name: "get_logs", Input: json.RawMessage(`{}`)},
+		}},
+		{Role: "user", Blocks: []ai.ContentBlock{
+			{Type: ai.BlockToolResult, ToolUseID: "t1", Content: "boom"},
+		}},
+		{Role: "assistant", Blocks: []ai.ContentBlock{
+			{Type: ai.BlockText, Text: "It panics."},
+		}},
+		{Role: "user", Content: "fix?"},
+		{Role: "assistant", Content: "Error: nope", Notice: true},
+	}
+
+	assert.Equal(t, []int{0, 3, 4}, chatStops(mm))
+}
+
+func TestStepCursor(t *testing.T) {
+	stops := []int{0, 3, 4, 7}
+
+	uu := map[string]struct {
+		stops   []int
+		cursor  int
+		forward bool
+		e       int
+	}{
+		"empty": {
+			cursor:  noCursor,
+			forward: true,
+			e:       noCursor,
+		},
+		"none-forward": {
+			stops:   stops,
+			cursor:  noCursor,
+			forward: true,
+			e:       7,
+		},
+		"none-back": {
+			stops:  stops,
+			cursor: noCursor,
+			e:      7,
+		},
+		"forward": {
+			stops:   stops,
+			cursor:  0,
+			forward: true,
+			e:       3,
+		},
+		"back": {
+			stops:  stops,
+			cursor: 4,
+			e:      3,
+		},
+		"first": {
+			stops:  stops,
+			cursor: 0,
+			e:      0,
+		},
+		"last": {
+			stops:   stops,
+			cursor:  7,
+			forward: true,
+			e:       7,
+		},
+		"between": {
+			stops:   stops,
+			cursor:  5,
+			forward: true,
+			e:       7,
+		},
+	}
+
+	for k := range uu {
+		u := uu[k]
+		t.Run(k, func(t *testing.T) {
+			assert.Equal(t, u.e, stepCursor(u.stops, u.cursor, u.forward))
+		})
+	}
+}
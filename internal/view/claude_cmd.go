@@ -0,0 +1,96 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of K9s
+
+package view
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/quentincherifi/c9s/internal/ai"
+	"github.com/quentincherifi/c9s/internal/dao"
+	"github.com/quentincherifi/c9s/internal/ui/dialog"
+	"github.com/quentincherifi/c9s/internal/view/cmd"
+)
+
+// translateCmd asks Claude for the k9s command matching a request. Once
+// validated, the command is shown for confirmation before it runs.
+func translateCmd(app *App, request string) {
+	if app.command == nil || app.command.alias == nil {
+		app.Flash().Err(errors.New("no connection available"))
+		return
+	}
+	if app.Config.K9s.AIPolicy().IsDisabled() {
+		app.Flash().Err(ai.ErrPolicyDisabled)
+		return
+	}
+	cfg := app.Config.K9s.ActiveAI()
+
+	alias := app.command.alias
+	k := ai.K8sContext{Namespace: app.Config.ActiveNamespace()}
+	app.Flash().Infof("Translating %q...", request)
+	go func() {
+		c, err := configClient(cfg)
+		if err != nil {
+			app.QueueUpdateDraw(func() {
+				app.Flash().Err(err)
+			})
+			return
+		}
+		line, err := c.TranslateCommand(context.Background(), &k, request, commandAliases(alias))
+		if err == nil {
+			err = validateCmd(alias, line)
+		}
+		app.QueueUpdateDraw(func() {
+			if err != nil {
+				app.Flash().Errf("Unable to translate %q: %s", request, ai.Describe(err))
+				return
+			}
+			confirmCmd(app, line)
+		})
+	}()
+}
+
+// confirmCmd shows a validated command line for confirmation before it runs.
+func confirmCmd(app *App, line string) {
+	d := app.Styles.Dialog()
+	dialog.ShowConfirm(&d, app.Content.Pages, "Run Command", fmt.Sprintf("Run `:%s`?", line), func() {
+		app.gotoResource(line, "", false, true)
+	}, func() {})
+}
+
+// validateCmd checks a command line targets a known resource with a valid
+// label selector.
+func validateCmd(alias *dao.Alias, line string) error {
+	p := cmd.NewInterpreter(line)
+	if p.IsBlank() {
+		return ai.ErrNoCommand
+	}
+	if _, ok := alias.Resolve(cmd.NewInterpreter(line)); !ok {
+		return fmt.Errorf("`%s` command not found", p.Cmd())
+	}
+	if _, err := p.LabelsSelector(); err != nil {
+		return fmt.Errorf("invalid label selector in `%s`: %w", line, err)
+	}
+
+	return nil
+}
+
+// commandAliases lists the known resource aliases, fully qualified
+// resource names excluded.
+func commandAliases(alias *dao.Alias) []string {
+	var aa []string
+	for _, ss := range alias.ShortNames() {
+		for _, s := range ss {
+			if !strings.Contains(s, "/") {
+				aa = append(aa, s)
+			}
+		}
+	}
+	slices.Sort(aa)
+
+	return aa
+}
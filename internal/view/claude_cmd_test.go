@@ -0,0 +1,65 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of K9s
+
+package view
+
+import (
+	"testing"
+
+	"github.com/quentincherifi/c9s/internal/client"
+	"github.com/quentincherifi/c9s/internal/config"
+	"github.com/quentincherifi/c9s/internal/dao"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateCmd(t *testing.T) {
+	alias := &dao.Alias{Aliases: config.NewAliases()}
+	alias.Define(client.PodGVR, "po", "pod", "pods", client.PodGVR.String())
+	alias.Define(client.NewGVR("pod default"), "pd")
+
+	uu := map[string]struct {
+		line string
+		err  string
+	}{
+		"happy": {
+			line: "pods payments tier=api /crash",
+		},
+		"alias": {
+			line: "pd",
+		},
+		"set-labels": {
+			line: "po payments 'tier in (api,web)'",
+		},
+		"blank": {
+			err: "no command was suggested",
+		},
+		"unknown": {
+			line: "fred payments",
+			err:  "`fred` command not found",
+		},
+		"bad-labels": {
+			line: "pods payments tier==!api",
+			err:  "invalid label selector in `pods payments tier==!api`",
+		},
+	}
+
+	for k := range uu {
+		u := uu[k]
+		t.Run(k, func(t *testing.T) {
+			err := validateCmd(alias, u.line)
+			if u.err == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, u.err)
+		})
+	}
+}
+
+func TestCommandAliases(t *testing.T) {
+	alias := &dao.Alias{Aliases: config.NewAliases()}
+	alias.Define(client.PodGVR, "po", "pods", client.PodGVR.String())
+	alias.Define(client.SvcGVR, "svc")
+
+	assert.Equal(t, []string{"po", "pods", "svc"}, commandAliases(alias))
+}
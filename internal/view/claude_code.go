@@ -0,0 +1,78 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of K9s
+
+package view
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/quentincherifi/c9s/internal/ui"
+	"github.com/quentincherifi/c9s/internal/ui/dialog"
+	"github.com/derailed/tcell/v2"
+	"github.com/derailed/tview"
+)
+
+// codeFunc acts on a code block picked from the conversation.
+type codeFunc func(ui.CodeBlock)
+
+func (c *Claude) copyCodeCmd(*tcell.EventKey) *tcell.EventKey {
+	c.pickCode("Copy Code", func(b ui.CodeBlock) {
+		if err := clipboardWrite(b.Code); err != nil {
+			c.app.Flash().Err(err)
+			return
+		}
+		c.app.Flash().Info("Code block copied to clipboard...")
+	})
+
+	return nil
+}
+
+func (c *Claude) runCodeCmd(*tcell.EventKey) *tcell.EventKey {
+	c.pickCode("Run Code", c.runCode)
+
+	return nil
+}
+
+// runCode runs a single line code block as a k9s command, i.e. `:pods -n fred`.
+// As with translated commands, the command is validated and confirmed first.
+func (c *Claude) runCode(b ui.CodeBlock) {
+	cmd := strings.TrimSpace(b.Code)
+	if cmd == "" || strings.Contains(cmd, "\n") {
+		c.app.Flash().Warn("Only single line code blocks may be run as a command")
+		return
+	}
+	if c.app.command == nil || c.app.command.alias == nil {
+		c.app.Flash().Err(errors.New("no connection available"))
+		return
+	}
+	line := strings.TrimSpace(strings.TrimPrefix(cmd, ":"))
+	if err := validateCmd(c.app.command.alias, line); err != nil {
+		c.app.Flash().Errf("Unable to run `%s`: %v", line, err)
+		return
+	}
+	confirmCmd(c.app, line)
+}
+
+// pickCode hands the only code block to fn or lets users pick one first.
+func (c *Claude) pickCode(title string, fn codeFunc) {
+	bb := c.codeBlocks
+	switch len(bb) {
+	case 0:
+		c.app.Flash().Info("No code blocks yet")
+	case 1:
+		fn(bb[0])
+	default:
+		oo := make([]string, 0, len(bb))
+		for i, b := range bb {
+			oo = append(oo, fmt.Sprintf("[%d] %s", i+1, tview.Escape(b.Label())))
+		}
+		d := c.app.Styles.Dialog()
+		dialog.ShowSelection(&d, c.app.Content.Pages, title, oo, func(i int) {
+			if i >= 0 && i < len(bb) {
+				fn(bb[i])
+			}
+		})
+	}
+}
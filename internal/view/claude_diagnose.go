@@ -0,0 +1,123 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of K9s
+
+package view
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/quentincherifi/c9s/internal/ai"
+	"github.com/quentincherifi/c9s/internal/client"
+	"github.com/quentincherifi/c9s/internal/config/data"
+	"github.com/quentincherifi/c9s/internal/ui"
+)
+
+// diagnose gathers evidence about the resource selected in the current view,
+// asks Claude for its root cause and shows the resulting report once saved
+// along with the screen dumps.
+func diagnose(app *App) {
+	gvr, path := topTarget(app)
+	if gvr == nil || path == "" {
+		app.Flash().Warn("Select a pod or workload to diagnose")
+		return
+	}
+	if app.factory == nil || !ai.IsK8sResource(gvr) {
+		app.Flash().Warnf("Unable to diagnose %s", path)
+		return
+	}
+	policy := app.Config.K9s.AIPolicy()
+	if policy.IsDisabled() {
+		app.Flash().Err(ai.ErrPolicyDisabled)
+		return
+	}
+	if !policy.AllowsContent() {
+		app.Flash().Err(ai.ErrPolicyContent)
+		return
+	}
+	cfg := app.Config.K9s.ActiveAI()
+
+	k := ai.K8sContext{ContextName: app.Config.ActiveContextName()}
+	if cl, err := app.Conn().Config().CurrentClusterName(); err == nil {
+		k.ClusterName = cl
+	}
+	app.Flash().Infof("Diagnosing %s...", path)
+	go func() {
+		c, err := configClient(cfg)
+		if err != nil {
+			app.QueueUpdateDraw(func() {
+				app.Flash().Err(err)
+			})
+			return
+		}
+		c.SetRetryFunc(func(attempt, maxRetries int, wait time.Duration, err error) {
+			app.QueueUpdateDraw(func() {
+				app.Flash().Warnf("Diagnose request failed, retrying in %s (%d/%d): %v", wait.Round(time.Second), attempt, maxRetries, err)
+			})
+		})
+		ctx := context.Background()
+		e, err := ai.GatherEvidence(ctx, app.factory, gvr, path, cfg)
+		if err == nil {
+			err = e.Restrict(policy)
+		}
+		if err != nil {
+			app.QueueUpdateDraw(func() {
+				app.Flash().Errf("Unable to gather evidence: %v", err)
+			})
+			return
+		}
+		d, err := c.Diagnose(ctx, &k, e)
+		if err != nil {
+			app.QueueUpdateDraw(func() {
+				app.Flash().Err(fmt.Errorf("diagnose failed: %s", ai.Describe(err)))
+			})
+			return
+		}
+		// Reports are kept on disk, mask the description as sent.
+		e.Describe = c.Redact(e.Describe)
+		raw := d.Markdown()
+		fpath, err := saveDiagnosis(app.Config.K9s.ContextScreenDumpDir(), path, raw)
+		app.QueueUpdateDraw(func() {
+			if err != nil {
+				app.Flash().Errf("Unable to save diagnosis: %v", err)
+			} else {
+				app.Flash().Infof("Diagnosis saved to %s (confidence %s)", fpath, d.Confidence)
+			}
+			md := ui.NewMarkdown(ui.NewMarkdownStyle(app.Styles))
+			details := NewDetails(app, "Diagnosis", path, contentTXT, true).Update(md.Render(raw))
+			if err := app.inject(details, false); err != nil {
+				app.Flash().Err(err)
+			}
+		})
+	}()
+}
+
+// topTarget returns the resource selected in the current view if any.
+func topTarget(app *App) (*client.GVR, string) {
+	switch v := app.Content.Top().(type) {
+	case ResourceViewer:
+		if tbl := v.GetTable(); tbl != nil {
+			return v.GVR(), tbl.GetSelectedItem()
+		}
+	case promptRunner:
+		return v.selectedTarget()
+	}
+
+	return nil, ""
+}
+
+func saveDiagnosis(dir, path, raw string) (string, error) {
+	if err := data.EnsureFullPath(dir, data.DefaultDirMod); err != nil {
+		return "", err
+	}
+	f := fmt.Sprintf("diagnose-%s-%d.md", path, time.Now().Unix())
+	fpath := filepath.Join(dir, data.SanitizeFileName(f))
+	if err := os.WriteFile(fpath, []byte(raw), data.DefaultFileMod); err != nil {
+		return "", err
+	}
+
+	return fpath, nil
+}
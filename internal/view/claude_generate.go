@@ -0,0 +1,134 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of K9s
+
+package view
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/quentincherifi/c9s/internal/ai"
+	"github.com/quentincherifi/c9s/internal/ui/dialog"
+)
+
+// generateManifest asks Claude for a manifest matching a description. The
+// manifest is dry-run against the cluster and sent back for repairs until
+// valid. It is then opened in the editor and only applied once confirmed.
+func generateManifest(app *App, desc string) {
+	f := app.factory
+	if f == nil || app.Conn() == nil || !app.Conn().ConnectionOK() {
+		app.Flash().Err(errors.New("no connection available"))
+		return
+	}
+	if app.Config.K9s.AIPolicy().IsDisabled() {
+		app.Flash().Err(ai.ErrPolicyDisabled)
+		return
+	}
+	cfg := app.Config.K9s.ActiveAI()
+
+	ns := app.Config.ActiveNamespace()
+	k := ai.K8sContext{Namespace: ns}
+	dryRun := func(ctx context.Context, m string) error {
+		_, err := ai.ApplyManifest(ctx, f, ns, m, true)
+		return err
+	}
+	app.Flash().Infof("Generating manifest for %q...", desc)
+	go func() {
+		c, err := configClient(cfg)
+		if err != nil {
+			app.QueueUpdateDraw(func() {
+				app.Flash().Err(err)
+			})
+			return
+		}
+		gen, err := c.GenerateManifest(context.Background(), &k, desc, dryRun, cfg.GetMaxRepairs())
+		app.QueueUpdateDraw(func() {
+			if err != nil {
+				app.Flash().Errf("Unable to generate manifest: %s", ai.Describe(err))
+				return
+			}
+			path, err := saveYAML(app.Config.K9s.ContextScreenDumpDir(), "claude-manifest", gen.Manifest)
+			if err != nil {
+				app.Flash().Err(err)
+				return
+			}
+			if gen.Err != nil {
+				app.Flash().Warnf("Manifest still fails its dry-run after %d repairs: %s", gen.Repairs, gen.Err)
+			}
+			reviewManifest(app, ns, path)
+		})
+	}()
+}
+
+// reviewManifest opens a manifest in the editor, dry-runs the edited version
+// and asks for confirmation before applying it.
+func reviewManifest(app *App, ns, path string) {
+	if !edit(app, &shellOpts{clear: true, args: []string{path}}) {
+		app.Flash().Errf("Failed to launch editor")
+		return
+	}
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		app.Flash().Err(err)
+		return
+	}
+	manifest := string(raw)
+
+	go func() {
+		rr, err := ai.ApplyManifest(context.Background(), app.factory, ns, manifest, true)
+		app.QueueUpdateDraw(func() {
+			if err != nil {
+				app.Flash().Errf("Manifest %s failed its dry-run: %s", path, err)
+				return
+			}
+			if app.Config.IsReadOnly() {
+				app.Flash().Infof("Manifest %s is valid. Not applied in read-only mode", path)
+				return
+			}
+			app.Flash().Infof("Manifest saved in %s", path)
+			d := app.Styles.Dialog()
+			dialog.ShowConfirm(&d, app.Content.Pages, "Apply Manifest", applyPrompt(rr), func() {
+				applyManifest(app, ns, manifest)
+			}, func() {})
+		})
+	}()
+}
+
+// applyPrompt lists the resources a manifest creates or updates. Updates are
+// called out as they change live resources.
+func applyPrompt(rr []ai.AppliedResource) string {
+	var updates int
+	ll := make([]string, 0, len(rr))
+	for _, r := range rr {
+		if r.Update {
+			updates++
+		}
+		ll = append(ll, r.String())
+	}
+	msg := fmt.Sprintf("Apply %d resource(s)?", len(rr))
+	if updates > 0 {
+		msg += fmt.Sprintf(" %d existing resource(s) will be updated!", updates)
+	}
+
+	return msg + "\n- " + strings.Join(ll, "\n- ")
+}
+
+func applyManifest(app *App, ns, manifest string) {
+	go func() {
+		rr, err := ai.ApplyManifest(context.Background(), app.factory, ns, manifest, false)
+		app.QueueUpdateDraw(func() {
+			if err != nil {
+				app.Flash().Errf("Failed to apply manifest: %s", err)
+				return
+			}
+			pp := make([]string, 0, len(rr))
+			for _, r := range rr {
+				pp = append(pp, r.Path)
+			}
+			app.Flash().Infof("Applied %s", strings.Join(pp, ", "))
+		})
+	}()
+}
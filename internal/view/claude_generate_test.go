@@ -0,0 +1,37 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of K9s
+
+package view
+
+import (
+	"testing"
+
+	"github.com/quentincherifi/c9s/internal/ai"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApplyPrompt(t *testing.T) {
+	uu := map[string]struct {
+		rr []ai.AppliedResource
+		e  string
+	}{
+		"create": {
+			rr: []ai.AppliedResource{{Path: "deployments ns1/redis"}},
+			e:  "Apply 1 resource(s)?\n- create deployments ns1/redis",
+		},
+		"update": {
+			rr: []ai.AppliedResource{
+				{Path: "deployments ns1/redis"},
+				{Path: "services ns1/redis", Update: true},
+			},
+			e: "Apply 2 resource(s)? 1 existing resource(s) will be updated!\n- create deployments ns1/redis\n- update services ns1/redis",
+		},
+	}
+
+	for k := range uu {
+		u := uu[k]
+		t.Run(k, func(t *testing.T) {
+			assert.Equal(t, u.e, applyPrompt(u.rr))
+		})
+	}
+}
@@ -0,0 +1,55 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of K9s
+
+package view
+
+import (
+	"context"
+	"testing"
+
+	"github.com/quentincherifi/c9s/internal"
+	"github.com/quentincherifi/c9s/internal/ai"
+	"github.com/quentincherifi/c9s/internal/client"
+	"github.com/quentincherifi/c9s/internal/config/mock"
+	"github.com/derailed/tview"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClaudeSessionResumeFromBrowser(t *testing.T) {
+	app := NewApp(mock.NewMockConfig(t))
+	s := NewClaudeSessions(client.CsGVR)
+	require.NoError(t, s.Init(context.WithValue(context.Background(), internal.KeyApp, app)))
+	dir := t.TempDir()
+	prev := ai.NewSession("cl1", "ctx1")
+	prev.Messages = []ai.Message{{Role: "user", Content: "Why is blee failing?"}}
+	require.NoError(t, prev.Save(dir))
+	ctx := context.WithValue(context.Background(), internal.KeyFactory, testFactory{})
+	ctx = context.WithValue(ctx, internal.KeyDir, dir)
+	require.NoError(t, s.GetTable().GetModel().Refresh(ctx))
+	require.Equal(t, 1, s.GetTable().GetFilteredData().RowCount())
+	app.Content.Push(s)
+	assert.Empty(t, NewClaude(app, "").snapshot().Rows)
+
+	c := NewClaudeSession(app, &ai.Session{ID: "fred", GVR: "v1/pods", Path: "default/fred"})
+	k := c.snapshot()
+	assert.Empty(t, k.Rows)
+	assert.Empty(t, k.RowsSource)
+	assert.Equal(t, "pods", k.ResourceType)
+	assert.Equal(t, "default/fred", k.SelectedResource)
+}
+
+func TestAskClaudeDraft(t *testing.T) {
+	app := NewApp(mock.NewMockConfig(t))
+	askClaude(app, client.PodGVR, "default/fred")
+
+	c, ok := app.Content.Top().(*Claude)
+	require.True(t, ok)
+	assert.Empty(t, c.messages)
+	assert.Equal(t, "Explain this pod and diagnose any issue you can spot.", c.draft)
+
+	c.Focus(func(tview.Primitive) {})
+	assert.Empty(t, c.draft)
+	assert.True(t, c.InCmdMode())
+	assert.Equal(t, "Explain this pod and diagnose any issue you can spot.", c.cmdBuff.GetText())
+}
@@ -0,0 +1,109 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of K9s
+
+package view
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/quentincherifi/c9s/internal/ai"
+	"github.com/quentincherifi/c9s/internal/client"
+	"github.com/quentincherifi/c9s/internal/ui/dialog"
+	"github.com/derailed/tcell/v2"
+)
+
+const maxJumpLabel = 80
+
+// LineJumpFunc scrolls a log view to a given 1 based line.
+type LineJumpFunc func(line int)
+
+// NewClaudeLogs returns a Claude view explaining a log buffer. Lines cited by
+// Claude can be jumped back to in the originating log view. Stamps tracks
+// the timestamp of each line if known.
+func NewClaudeLogs(app *App, gvr *client.GVR, path string, lines, stamps []string, source string, jump LineJumpFunc) *Claude {
+	c := NewClaudeFor(app, gvr, path, ai.LogQuestion)
+	cfg := app.Config.K9s.AI
+	excerpt, n := ai.NumberLines(lines, cfg.GetMaxLogLines(), cfg.GetMaxLogsSize())
+	c.k8sContext.Logs = excerpt
+	c.k8sContext.LogSource = logExcerptSource(source, stamps, n, len(lines))
+	c.logLines, c.jumpFn = lines, jump
+
+	return c
+}
+
+// logExcerptSource describes the last n lines sent out of total. The time
+// window spans the lines actually sent.
+func logExcerptSource(source string, stamps []string, n, total int) string {
+	src := fmt.Sprintf("%s, last %d of %d lines", source, n, total)
+	if n == 0 || len(stamps) != total {
+		return src
+	}
+	if first, last := stamps[total-n], stamps[total-1]; first != "" {
+		src += fmt.Sprintf(", from %s to %s", first, last)
+	}
+
+	return src
+}
+
+func (c *Claude) lineRefs() []int {
+	var sb strings.Builder
+	for i := range c.messages {
+		if c.messages[i].Role == "assistant" && !c.messages[i].Notice {
+			sb.WriteString(c.messages[i].Text())
+			sb.WriteString("\n")
+		}
+	}
+
+	refs := make([]int, 0)
+	for _, n := range ai.LineRefs(sb.String()) {
+		if n <= len(c.logLines) {
+			refs = append(refs, n)
+		}
+	}
+
+	return refs
+}
+
+func (c *Claude) jumpCmd(*tcell.EventKey) *tcell.EventKey {
+	if c.isStreaming() {
+		c.app.Flash().Warn("Claude is still responding. Press Ctrl-X to cancel")
+		return nil
+	}
+
+	refs := c.lineRefs()
+	switch len(refs) {
+	case 0:
+		c.app.Flash().Info("No log lines referenced yet")
+	case 1:
+		c.jumpTo(refs[0])
+	default:
+		oo := make([]string, 0, len(refs))
+		for _, n := range refs {
+			oo = append(oo, c.jumpLabel(n))
+		}
+		d := c.app.Styles.Dialog()
+		dialog.ShowSelection(&d, c.app.Content.Pages, "Log Lines", oo, func(i int) {
+			if i >= 0 && i < len(refs) {
+				c.jumpTo(refs[i])
+			}
+		})
+	}
+
+	return nil
+}
+
+func (c *Claude) jumpLabel(n int) string {
+	l := []rune(strings.TrimSpace(c.logLines[n-1]))
+	if len(l) > maxJumpLabel {
+		l = append(l[:maxJumpLabel], '…')
+	}
+
+	return fmt.Sprintf("L%d %s", n, string(l))
+}
+
+// jumpTo heads back to the log view and scrolls to the given line.
+func (c *Claude) jumpTo(line int) {
+	c.app.Content.Pop()
+	c.jumpFn(line)
+}
@@ -0,0 +1,46 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of K9s
+
+package view
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLogExcerptSource(t *testing.T) {
+	stamps := []string{"10:00:00", "10:00:05", "10:01:00", "10:02:00"}
+
+	uu := map[string]struct {
+		stamps []string
+		n      int
+		e      string
+	}{
+		"all": {
+			stamps: stamps,
+			n:      4,
+			e:      "pod p1, last 4 of 4 lines, from 10:00:00 to 10:02:00",
+		},
+		"tail": {
+			stamps: stamps,
+			n:      2,
+			e:      "pod p1, last 2 of 4 lines, from 10:01:00 to 10:02:00",
+		},
+		"no-stamps": {
+			n: 2,
+			e: "pod p1, last 2 of 4 lines",
+		},
+		"none-kept": {
+			stamps: stamps,
+			e:      "pod p1, last 0 of 4 lines",
+		},
+	}
+
+	for k := range uu {
+		u := uu[k]
+		t.Run(k, func(t *testing.T) {
+			assert.Equal(t, u.e, logExcerptSource("pod p1", u.stamps, u.n, 4))
+		})
+	}
+}
@@ -0,0 +1,172 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of K9s
+
+package view
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/quentincherifi/c9s/internal/config"
+	"github.com/quentincherifi/c9s/internal/ui/dialog"
+	"github.com/derailed/tcell/v2"
+	"github.com/derailed/tview"
+)
+
+const (
+	// saveFlag persists a model switch to the configuration.
+	saveFlag = "--save"
+
+	// maxTemperature tracks the highest temperature providers accept.
+	maxTemperature = 2.0
+)
+
+// claudeModel switches the Claude model by alias or name. Without a name,
+// the configured aliases are listed to pick from.
+func claudeModel(app *App, args []string) {
+	args, persist := cutSaveFlag(args)
+	if len(args) == 0 {
+		pickModel(app, nil)
+		return
+	}
+	setModel(app, args[0], persist)
+}
+
+// claudeTokens sets the max tokens of Claude answers.
+func claudeTokens(app *App, args []string) {
+	args, persist := cutSaveFlag(args)
+	if len(args) == 0 {
+		app.Flash().Infof("Claude answers are capped at %d tokens", app.Config.K9s.ActiveAI().GetMaxTokens())
+		return
+	}
+	n, err := strconv.Atoi(args[0])
+	if err != nil || n <= 0 {
+		app.Flash().Errf("Invalid max tokens %q. Must be a positive number", args[0])
+		return
+	}
+	switchModel(app, config.AIModel{MaxTokens: n}, persist)
+}
+
+// claudeTemperature sets the sampling temperature of Claude answers.
+func claudeTemperature(app *App, args []string) {
+	args, persist := cutSaveFlag(args)
+	if len(args) == 0 {
+		if t := app.Config.K9s.ActiveAI().Temperature; t != nil {
+			app.Flash().Infof("Claude answers use temperature %s", strconv.FormatFloat(*t, 'f', -1, 64))
+		} else {
+			app.Flash().Info("Claude answers use the provider default temperature")
+		}
+		return
+	}
+	t, err := strconv.ParseFloat(args[0], 64)
+	if err != nil || t < 0 || t > maxTemperature {
+		app.Flash().Errf("Invalid temperature %q. Must be a number between 0 and %g", args[0], maxTemperature)
+		return
+	}
+	switchModel(app, config.AIModel{Temperature: &t}, persist)
+}
+
+// claudeSystemPrompt sets instructions added to the Claude system prompt.
+func claudeSystemPrompt(app *App, args []string) {
+	args, persist := cutSaveFlag(args)
+	if len(args) == 0 {
+		if p := app.Config.K9s.ActiveAI().SystemPrompt; p != "" {
+			app.Flash().Infof("Claude instructions: %s", p)
+		} else {
+			app.Flash().Info("No custom Claude instructions")
+		}
+		return
+	}
+	switchModel(app, config.AIModel{SystemPrompt: strings.Join(args, " ")}, persist)
+}
+
+func cutSaveFlag(args []string) ([]string, bool) {
+	i := slices.Index(args, saveFlag)
+	if i < 0 {
+		return args, false
+	}
+
+	return slices.Delete(slices.Clone(args), i, i+1), true
+}
+
+// setModel switches to a model alias or name. The default alias drops the
+// session overrides.
+func setModel(app *App, name string, persist bool) {
+	if name == config.DefaultAIModelAlias {
+		app.Config.K9s.ResetAI()
+		app.Flash().Infof("Claude switched back to %s", app.Config.K9s.ActiveAI().CurrentModel())
+		return
+	}
+	if !switchModel(app, app.Config.K9s.AI.ResolveModel(name), persist) {
+		return
+	}
+	if !app.Config.K9s.AI.IsModelAlias(name) {
+		app.Flash().Warnf("%q is not a model alias and is used as a model name. Check your provider knows it", name)
+	}
+}
+
+// switchModel applies model settings to the current session and optionally
+// saves them as the new defaults. Models can not be switched while the
+// context policy forces a provider.
+func switchModel(app *App, m config.AIModel, persist bool) bool {
+	if p := app.Config.K9s.AIPolicy(); m.Model != "" && p.ForcesProvider() {
+		app.Flash().Errf("The context AI policy forces provider %s. Models can not be switched", p.Provider)
+		return false
+	}
+	app.Config.K9s.OverrideAI(m)
+	current := app.Config.K9s.ActiveAI().CurrentModel()
+	if !persist {
+		app.Flash().Infof("Claude switched to %s for this session", current)
+		return true
+	}
+	app.Config.K9s.AI.ApplyModel(m)
+	if err := app.Config.Save(true); err != nil {
+		app.Flash().Errf("Failed to save config: %v", err)
+		return false
+	}
+	app.Flash().Infof("Claude switched to %s and saved", current)
+
+	return true
+}
+
+// pickModel lists the configured model aliases to switch to for the current
+// session. done is called once a model is picked.
+func pickModel(app *App, done func()) {
+	if p := app.Config.K9s.AIPolicy(); p.ForcesProvider() {
+		app.Flash().Errf("The context AI policy forces provider %s. Models can not be switched", p.Provider)
+		return
+	}
+	cfg := &app.Config.K9s.AI
+	names := []string{config.DefaultAIModelAlias}
+	oo := []string{fmt.Sprintf("%s: %s", config.DefaultAIModelAlias, tview.Escape(cfg.CurrentModel().String()))}
+	for _, a := range cfg.ModelAliases() {
+		if a == config.DefaultAIModelAlias {
+			continue
+		}
+		names = append(names, a)
+		oo = append(oo, fmt.Sprintf("%s: %s", tview.Escape(a), tview.Escape(cfg.Models[a].String())))
+	}
+	if len(names) == 1 {
+		app.Flash().Infof("No model aliases configured. Using %s", app.Config.K9s.ActiveAI().CurrentModel())
+		return
+	}
+
+	d := app.Styles.Dialog()
+	dialog.ShowSelection(&d, app.Content.Pages, "Claude Models", oo, func(i int) {
+		if i < 0 || i >= len(names) {
+			return
+		}
+		setModel(app, names[i], false)
+		if done != nil {
+			done()
+		}
+	})
+}
+
+func (c *Claude) modelCmd(*tcell.EventKey) *tcell.EventKey {
+	pickModel(c.app, c.updateContextDisplay)
+
+	return nil
+}
@@ -0,0 +1,185 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of K9s
+
+package view
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/quentincherifi/c9s/internal/client"
+	"github.com/quentincherifi/c9s/internal/config"
+	"github.com/quentincherifi/c9s/internal/ui"
+	"github.com/quentincherifi/c9s/internal/ui/dialog"
+	"github.com/derailed/tcell/v2"
+)
+
+// promptRunner represents a view prompts may be sent from.
+type promptRunner interface {
+	Runner
+
+	selectedTarget() (*client.GVR, string)
+}
+
+// promptInScope checks if a prompt applies to a view. Prompts without
+// scopes apply everywhere.
+func promptInScope(p *config.AIPrompt, r promptRunner) bool {
+	if len(p.Scopes) == 0 || hasAll(p.Scopes) {
+		return true
+	}
+	if r == nil {
+		return false
+	}
+
+	return inScope(p.Scopes, r.Aliases())
+}
+
+func promptActions(r promptRunner, aa *ui.KeyActions) error {
+	aa.Range(func(k tcell.Key, a ui.KeyAction) {
+		if a.Opts.Prompt {
+			aa.Delete(k)
+		}
+	})
+
+	pp := config.NewAIPrompts()
+	if err := pp.Load(r.App().Config.ContextPromptsPath()); err != nil {
+		return err
+	}
+
+	var errs error
+	for _, k := range pp.Names() {
+		p := pp.Prompts[k]
+		if p.ShortCut == "" || !promptInScope(&p, r) {
+			continue
+		}
+		key, err := asKey(p.ShortCut)
+		if err != nil {
+			errs = errors.Join(errs, err)
+			continue
+		}
+		if _, ok := aa.Get(key); ok {
+			errs = errors.Join(errs, fmt.Errorf("duplicate prompt key found for %q in %q", p.ShortCut, k))
+			continue
+		}
+		aa.Add(key, ui.NewKeyActionWithOpts(
+			p.Title(k),
+			promptAction(r, &p),
+			ui.ActionOpts{
+				Visible: true,
+				Prompt:  true,
+			},
+		))
+	}
+
+	return errs
+}
+
+func promptAction(r promptRunner, p *config.AIPrompt) ui.ActionHandler {
+	return func(evt *tcell.EventKey) *tcell.EventKey {
+		if _, path := r.selectedTarget(); path == "" {
+			return evt
+		}
+		sendPrompt(r.App(), r, p)
+
+		return nil
+	}
+}
+
+// showPrompts sends the named prompt to Claude or lets users pick one
+// applying to the current view.
+func showPrompts(app *App, name string) {
+	pp := config.NewAIPrompts()
+	if err := pp.Load(app.Config.ContextPromptsPath()); err != nil {
+		app.Flash().Err(err)
+		return
+	}
+	r, _ := app.Content.Top().(promptRunner)
+
+	if name != "" {
+		p, ok := pp.Prompts[name]
+		if !ok {
+			app.Flash().Errf("Unknown prompt %q", name)
+			return
+		}
+		sendPrompt(app, r, &p)
+		return
+	}
+
+	var (
+		names  []string
+		labels []string
+	)
+	for _, k := range pp.Names() {
+		p := pp.Prompts[k]
+		if !promptInScope(&p, r) {
+			continue
+		}
+		names, labels = append(names, k), append(labels, p.Title(k))
+	}
+	if len(names) == 0 {
+		app.Flash().Info("No prompts available for this view")
+		return
+	}
+
+	d := app.Styles.Dialog()
+	dialog.ShowSelection(&d, app.Content.Pages, "Prompts", labels, func(i int) {
+		if i < 0 || i >= len(names) {
+			return
+		}
+		p := pp.Prompts[names[i]]
+		sendPrompt(app, r, &p)
+	})
+}
+
+// sendPrompt opens a Claude view asking the given prompt about the resource
+// selected in the view if any. Prompts referring to variables that cannot be
+// resolved are not sent.
+func sendPrompt(app *App, r promptRunner, p *config.AIPrompt) {
+	var (
+		gvr  *client.GVR
+		path string
+		env  Env
+	)
+	if r != nil {
+		gvr, path = r.selectedTarget()
+	}
+	if gvr != nil && path != "" && r.EnvFn() != nil {
+		env = r.EnvFn()()
+	}
+	text, err := substitutePrompt(p.Prompt, env)
+	if err != nil {
+		app.Flash().Warnf("Prompt not sent: %v", err)
+		return
+	}
+
+	var v *Claude
+	if gvr != nil && path != "" {
+		v = NewClaudeFor(app, gvr, path, text)
+	} else {
+		v = NewClaude(app, text)
+	}
+	if err := app.inject(v, false); err != nil {
+		app.Flash().Err(err)
+	}
+}
+
+// substitutePrompt replaces the variables of a prompt with their values.
+// It fails when some variables remain unresolved.
+func substitutePrompt(text string, env Env) (string, error) {
+	if env != nil {
+		s, err := env.Substitute(text)
+		if err != nil {
+			return "", err
+		}
+		text = s
+	}
+	vv := envRX.FindAllString(text, -1)
+	if len(vv) == 0 {
+		return text, nil
+	}
+	slices.Sort(vv)
+
+	return "", fmt.Errorf("unresolved variables %s", strings.Join(slices.Compact(vv), ", "))
+}
@@ -0,0 +1,109 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of K9s
+
+package view
+
+import (
+	"testing"
+
+	"github.com/quentincherifi/c9s/internal/client"
+	"github.com/quentincherifi/c9s/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+func TestPromptInScope(t *testing.T) {
+	r := &promptRunnerMock{aliases: sets.New("deploy", "deployments")}
+
+	uu := map[string]struct {
+		scopes []string
+		r      promptRunner
+		e      bool
+	}{
+		"unscoped": {
+			r: r,
+			e: true,
+		},
+		"unscoped-no-view": {
+			e: true,
+		},
+		"all": {
+			scopes: []string{AllScopes},
+			e:      true,
+		},
+		"match": {
+			scopes: []string{"sts", "deploy"},
+			r:      r,
+			e:      true,
+		},
+		"no-match": {
+			scopes: []string{"sts"},
+			r:      r,
+		},
+		"no-view": {
+			scopes: []string{"deploy"},
+		},
+	}
+
+	for k := range uu {
+		u := uu[k]
+		t.Run(k, func(t *testing.T) {
+			p := config.AIPrompt{Scopes: u.scopes}
+			assert.Equal(t, u.e, promptInScope(&p, u.r))
+		})
+	}
+}
+
+func TestSubstitutePrompt(t *testing.T) {
+	uu := map[string]struct {
+		text string
+		env  Env
+		e    string
+		err  string
+	}{
+		"plain": {
+			text: "Why is it failing?",
+			e:    "Why is it failing?",
+		},
+		"resolved": {
+			text: "Why is $NAME failing in ${NAMESPACE}?",
+			env:  Env{"NAME": "fred", "NAMESPACE": "default"},
+			e:    "Why is fred failing in default?",
+		},
+		"no-env": {
+			text: "Why is $NAME failing in $NAMESPACE? Check $NAME.",
+			err:  "unresolved variables $NAME, $NAMESPACE",
+		},
+		"unknown": {
+			text: "Why is $NAME failing on $NODE?",
+			env:  Env{"NAME": "fred"},
+			err:  "unresolved variables $NODE",
+		},
+	}
+
+	for k := range uu {
+		u := uu[k]
+		t.Run(k, func(t *testing.T) {
+			s, err := substitutePrompt(u.text, u.env)
+			if u.err != "" {
+				require.EqualError(t, err, u.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, u.e, s)
+		})
+	}
+}
+
+type promptRunnerMock struct {
+	aliases sets.Set[string]
+}
+
+func (*promptRunnerMock) App() *App                   { return nil }
+func (*promptRunnerMock) GetSelectedItem() string     { return "" }
+func (p *promptRunnerMock) Aliases() sets.Set[string] { return p.aliases }
+func (*promptRunnerMock) EnvFn() EnvFunc              { return nil }
+func (*promptRunnerMock) selectedTarget() (*client.GVR, string) {
+	return client.DpGVR, "default/fred"
+}
@@ -0,0 +1,158 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of K9s
+
+package view
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/quentincherifi/c9s/internal"
+	"github.com/quentincherifi/c9s/internal/ai"
+	"github.com/quentincherifi/c9s/internal/client"
+	"github.com/quentincherifi/c9s/internal/config/data"
+	"github.com/quentincherifi/c9s/internal/ui"
+	"github.com/derailed/tcell/v2"
+	"github.com/derailed/tview"
+)
+
+const (
+	sessionRenamePage  = "session-rename"
+	sessionRenameField = "Title:"
+)
+
+// ClaudeSessions presents persisted Claude conversations.
+type ClaudeSessions struct {
+	ResourceViewer
+}
+
+// NewClaudeSessions returns a new viewer.
+func NewClaudeSessions(gvr *client.GVR) ResourceViewer {
+	s := ClaudeSessions{
+		ResourceViewer: NewBrowser(gvr),
+	}
+	s.GetTable().SetBorderFocusColor(tcell.ColorAqua)
+	s.GetTable().SetSelectedStyle(tcell.StyleDefault.Foreground(tcell.ColorWhite).Background(tcell.ColorDarkCyan).Attributes(tcell.AttrNone))
+	s.GetTable().SetSortCol(ageCol, true)
+	s.GetTable().SelectRow(1, 0, true)
+	s.GetTable().SetEnterFn(s.resume)
+	s.SetContextFn(s.dirContext)
+	s.AddBindKeysFn(s.bindKeys)
+
+	return &s
+}
+
+func (s *ClaudeSessions) bindKeys(aa *ui.KeyActions) {
+	aa.Bulk(ui.KeyMap{
+		ui.KeyR: ui.NewKeyAction("Rename", s.renameCmd, true),
+		ui.KeyX: ui.NewKeyAction("Export", s.exportCmd, true),
+	})
+}
+
+func (s *ClaudeSessions) dirContext(ctx context.Context) context.Context {
+	dir := s.App().Config.K9s.ContextClaudeDir()
+	if err := data.EnsureFullPath(dir, ai.SessionDirMod); err != nil {
+		s.App().Flash().Err(err)
+		return ctx
+	}
+
+	return context.WithValue(ctx, internal.KeyDir, dir)
+}
+
+func (*ClaudeSessions) resume(app *App, _ ui.Tabular, _ *client.GVR, path string) {
+	session, err := ai.LoadSession(path)
+	if err != nil {
+		app.Flash().Err(err)
+		return
+	}
+	if err := app.inject(NewClaudeSession(app, session), false); err != nil {
+		app.Flash().Err(err)
+	}
+}
+
+func (s *ClaudeSessions) selectedSession() (*ai.Session, bool) {
+	path := s.GetTable().GetSelectedItem()
+	if path == "" {
+		return nil, false
+	}
+	session, err := ai.LoadSession(path)
+	if err != nil {
+		s.App().Flash().Err(err)
+		return nil, false
+	}
+
+	return session, true
+}
+
+func (s *ClaudeSessions) exportCmd(evt *tcell.EventKey) *tcell.EventKey {
+	session, ok := s.selectedSession()
+	if !ok {
+		return evt
+	}
+
+	path, err := exportSession(s.App().Config.K9s.ContextScreenDumpDir(), session)
+	if err != nil {
+		s.App().Flash().Err(err)
+		return nil
+	}
+	s.App().Flash().Infof("Session exported to %s", path)
+
+	return nil
+}
+
+func (s *ClaudeSessions) renameCmd(evt *tcell.EventKey) *tcell.EventKey {
+	session, ok := s.selectedSession()
+	if !ok {
+		return evt
+	}
+	s.showRenameModal(session)
+
+	return nil
+}
+
+func (s *ClaudeSessions) rename(session *ai.Session, title string) error {
+	session.Title = strings.TrimSpace(title)
+
+	return session.Save(s.App().Config.K9s.ContextClaudeDir())
+}
+
+func (s *ClaudeSessions) showRenameModal(session *ai.Session) {
+	app := s.App()
+	styles := app.Styles.Dialog()
+
+	f := tview.NewForm().
+		SetItemPadding(0).
+		SetButtonsAlign(tview.AlignCenter).
+		SetButtonBackgroundColor(styles.ButtonBgColor.Color()).
+		SetButtonTextColor(styles.ButtonFgColor.Color()).
+		SetLabelColor(styles.LabelFgColor.Color()).
+		SetFieldTextColor(styles.FieldFgColor.Color())
+	f.AddInputField(sessionRenameField, session.Title, 0, nil, nil).
+		AddButton("OK", func() {
+			input := f.GetFormItemByLabel(sessionRenameField).(*tview.InputField)
+			if err := s.rename(session, input.GetText()); err != nil {
+				app.Flash().Err(err)
+				return
+			}
+			app.Content.RemovePage(sessionRenamePage)
+			s.Refresh()
+		}).
+		AddButton("Cancel", func() {
+			app.Content.RemovePage(sessionRenamePage)
+		})
+
+	m := tview.NewModalForm("<Rename>", f)
+	m.SetText(fmt.Sprintf("Rename session %q?", session.Name()))
+	m.SetDoneFunc(func(int, string) {
+		app.Content.RemovePage(sessionRenamePage)
+	})
+	app.Content.AddPage(sessionRenamePage, m, false, false)
+	app.Content.ShowPage(sessionRenamePage)
+
+	for i := range f.GetButtonCount() {
+		f.GetButton(i).
+			SetBackgroundColorActivated(styles.ButtonFocusBgColor.Color()).
+			SetLabelColorActivated(styles.ButtonFocusFgColor.Color())
+	}
+}
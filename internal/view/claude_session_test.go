@@ -0,0 +1,21 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright Authors of K9s
+
+package view_test
+
+import (
+	"testing"
+
+	"github.com/quentincherifi/c9s/internal/client"
+	"github.com/quentincherifi/c9s/internal/view"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClaudeSessionsNew(t *testing.T) {
+	s := view.NewClaudeSessions(client.CsGVR)
+
+	require.NoError(t, s.Init(makeCtx(t)))
+	assert.Equal(t, "ClaudeSessions", s.Name())
+	assert.Len(t, s.Hints(), 9)
+}
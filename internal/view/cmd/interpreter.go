@@ -330,3 +330,18 @@ func (c *Interpreter) ClaudeArgs() []string {
 	}
 	return strings.Fields(args)
 }
+
+// IsPromptsCmd returns true if AI prompts cmd is detected.
+func (c *Interpreter) IsPromptsCmd() bool {
+	return promptsCmd.Has(c.cmd)
+}
+
+// PromptArg returns the prompt name if any.
+func (c *Interpreter) PromptArg() (string, bool) {
+	if !c.IsPromptsCmd() {
+		return "", false
+	}
+	n := c.Args()
+
+	return n, n != ""
+}
@@ -632,6 +632,39 @@ func TestCowCmd(t *testing.T) {
 	}
 }
 
+func TestPromptsCmd(t *testing.T) {
+	uu := map[string]struct {
+		cmd, name string
+		ok, named bool
+	}{
+		"empty": {},
+		"plain": {
+			cmd: "prompts",
+			ok:  true,
+		},
+		"named": {
+			cmd:   "prompt stuck-rollout",
+			ok:    true,
+			name:  "stuck-rollout",
+			named: true,
+		},
+		"toast": {
+			cmd: "promptsy",
+		},
+	}
+
+	for k := range uu {
+		u := uu[k]
+		t.Run(k, func(t *testing.T) {
+			p := cmd.NewInterpreter(u.cmd)
+			assert.Equal(t, u.ok, p.IsPromptsCmd())
+			n, ok := p.PromptArg()
+			assert.Equal(t, u.named, ok)
+			assert.Equal(t, u.name, n)
+		})
+	}
+}
+
 func TestArgs(t *testing.T) {
 	uu := map[string]struct {
 		cmd string
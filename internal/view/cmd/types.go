@@ -78,4 +78,8 @@ var (
 		"claude",
 		"ai",
 	)
+	promptsCmd = sets.New(
+		"prompt",
+		"prompts",
+	)
 )
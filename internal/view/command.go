@@ -13,6 +13,7 @@ import (
 	"sync"
 
 	"github.com/quentincherifi/c9s/internal/client"
+	"github.com/quentincherifi/c9s/internal/config"
 	"github.com/quentincherifi/c9s/internal/dao"
 	"github.com/quentincherifi/c9s/internal/model"
 	"github.com/quentincherifi/c9s/internal/slogs"
@@ -307,6 +308,9 @@ func (c *Command) specialCmd(p *cmd.Interpreter, pushCmd bool) bool {
 		}
 	case p.IsClaudeCmd():
 		c.claudeCmd(p)
+	case p.IsPromptsCmd():
+		n, _ := p.PromptArg()
+		showPrompts(c.app, n)
 	default:
 		return false
 	}
@@ -317,14 +321,39 @@ func (c *Command) specialCmd(p *cmd.Interpreter, pushCmd bool) bool {
 func (c *Command) claudeCmd(p *cmd.Interpreter) {
 	args := p.ClaudeArgs()
 	if len(args) >= 2 && args[0] == "set-key" {
-		// Save API key to config
-		c.app.Config.K9s.AI.APIKey = args[1]
-		c.app.Config.K9s.AI.Enabled = true
-		if err := c.app.Config.Save(true); err != nil {
-			c.app.Flash().Errf("Failed to save API key: %v", err)
-		} else {
-			c.app.Flash().Info("Claude API key saved")
-		}
+		c.setAPIKey(args[1])
+		return
+	}
+	if len(args) == 1 && args[0] == "sessions" {
+		c.app.gotoResource(client.CsGVR.String(), "", false, true)
+		return
+	}
+	if len(args) >= 2 && args[0] == "cmd" {
+		translateCmd(c.app, strings.Join(args[1:], " "))
+		return
+	}
+	if len(args) >= 2 && args[0] == "generate" {
+		generateManifest(c.app, strings.Join(args[1:], " "))
+		return
+	}
+	if len(args) >= 1 && args[0] == "model" {
+		claudeModel(c.app, args[1:])
+		return
+	}
+	if len(args) >= 1 && args[0] == "tokens" {
+		claudeTokens(c.app, args[1:])
+		return
+	}
+	if len(args) >= 1 && args[0] == "temperature" {
+		claudeTemperature(c.app, args[1:])
+		return
+	}
+	if len(args) >= 1 && args[0] == "system" {
+		claudeSystemPrompt(c.app, args[1:])
+		return
+	}
+	if len(args) == 1 && args[0] == "diagnose" {
+		diagnose(c.app)
 		return
 	}
 
@@ -335,6 +364,30 @@ func (c *Command) claudeCmd(p *cmd.Interpreter) {
 	}
 }
 
+// setAPIKey obfuscates the API key to the key file, keeping it out of the
+// main config. The key file is only protected by its permissions.
+func (c *Command) setAPIKey(key string) {
+	cfg := &c.app.Config.K9s.AI
+	path := cfg.GetAPIKeyFile()
+	if err := config.SaveAPIKey(path, key); err != nil {
+		c.app.Flash().Errf("Failed to save API key: %v", err)
+		return
+	}
+	cfg.Enabled = true
+	if err := c.app.Config.Save(true); err != nil {
+		c.app.Flash().Errf("Failed to save config: %v", err)
+		return
+	}
+	switch {
+	case cfg.APIKeyCommand != "":
+		c.app.Flash().Warnf("Claude API key saved to %s but apiKeyCommand takes precedence", path)
+	case cfg.APIKey != "":
+		c.app.Flash().Warnf("Claude API key saved to %s. Remove the plaintext apiKey from %s", path, config.AppConfigFile)
+	default:
+		c.app.Flash().Infof("Claude API key saved to %s, obfuscated and readable by you only", path)
+	}
+}
+
 func (c *Command) viewMetaFor(p *cmd.Interpreter) (*client.GVR, *MetaViewer, *cmd.Interpreter, error) {
 	if c.alias == nil {
 		return client.NoGVR, nil, nil, fmt.Errorf("no connection available")
@@ -9,6 +9,7 @@ import (
 	"io"
 	"strings"
 
+	"github.com/quentincherifi/c9s/internal/client"
 	"github.com/quentincherifi/c9s/internal/config"
 	"github.com/quentincherifi/c9s/internal/model"
 	"github.com/quentincherifi/c9s/internal/ui"
@@ -39,6 +40,7 @@ type Details struct {
 	searchable                bool
 	fullScreen                bool
 	contentType               string
+	gvr                       *client.GVR
 }
 
 // NewDetails returns a details viewer.
@@ -60,6 +62,13 @@ func NewDetails(app *App, title, subject, contentType string, searchable bool) *
 	return &d
 }
 
+// ForResource tracks the resource being detailed, subject being its path.
+func (d *Details) ForResource(gvr *client.GVR) *Details {
+	d.gvr = gvr
+
+	return d
+}
+
 func (*Details) SetCommand(*cmd.Interpreter)            {}
 func (*Details) SetFilter(string, bool)                 {}
 func (*Details) SetLabelSelector(labels.Selector, bool) {}
@@ -151,6 +160,11 @@ func (d *Details) bindKeys() {
 	if !d.searchable {
 		d.actions.Delete(ui.KeyN, ui.KeyShiftN)
 	}
+	if d.gvr != nil {
+		d.actions.Bulk(askClaudeKey(d.app, func() (*client.GVR, string) {
+			return d.gvr, d.subject
+		}))
+	}
 }
 
 func (d *Details) keyboard(evt *tcell.EventKey) *tcell.EventKey {
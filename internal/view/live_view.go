@@ -11,6 +11,7 @@ import (
 	"strings"
 
 	"github.com/quentincherifi/c9s/internal"
+	"github.com/quentincherifi/c9s/internal/client"
 	"github.com/quentincherifi/c9s/internal/config"
 	"github.com/quentincherifi/c9s/internal/model"
 	"github.com/quentincherifi/c9s/internal/slogs"
@@ -160,6 +161,11 @@ func (v *LiveView) bindKeys() {
 	if !v.app.Config.IsReadOnly() {
 		v.actions.Add(ui.KeyE, ui.NewKeyAction("Edit", v.editCmd, true))
 	}
+	if v.model != nil {
+		v.actions.Bulk(askClaudeKey(v.app, func() (*client.GVR, string) {
+			return v.model.GVR(), v.model.GetPath()
+		}))
+	}
 	if v.title == yamlAction {
 		v.actions.Add(ui.KeyM, ui.NewKeyAction("Toggle ManagedFields", v.toggleManagedCmd, true))
 	}
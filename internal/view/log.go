@@ -14,6 +14,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/quentincherifi/c9s/internal/ai"
 	"github.com/quentincherifi/c9s/internal/client"
 	"github.com/quentincherifi/c9s/internal/color"
 	"github.com/quentincherifi/c9s/internal/config"
@@ -266,7 +267,11 @@ func (l *Log) bindKeys() {
 		ui.KeyW:         ui.NewKeyAction("Toggle Wrap", l.toggleTextWrapCmd, true),
 		tcell.KeyCtrlS:  ui.NewKeyAction("Save", l.SaveCmd, true),
 		ui.KeyC:         ui.NewKeyAction("Copy", cpCmd(l.app.Flash(), l.logs.TextView), true),
+		ui.KeyShiftE:    ui.NewKeyAction("Explain", l.explainCmd, true),
 	})
+	l.logs.Actions().Bulk(askClaudeKey(l.app, func() (*client.GVR, string) {
+		return l.model.GVR(), l.model.GetPath()
+	}))
 	if l.model.HasDefaultContainer() {
 		l.logs.Actions().Add(ui.KeyA, ui.NewKeyAction("Toggle AllContainers", l.toggleAllContainers, true))
 	}
@@ -465,6 +470,69 @@ func saveData(dir, fqn, logs string) (string, error) {
 	return path, nil
 }
 
+func (l *Log) explainCmd(evt *tcell.EventKey) *tcell.EventKey {
+	if l.app.InCmdMode() {
+		return evt
+	}
+
+	if p := l.app.Config.K9s.AIPolicy(); !p.AllowsLogs() {
+		err := ai.ErrPolicyLogs
+		if p.IsDisabled() {
+			err = ai.ErrPolicyDisabled
+		}
+		l.app.Flash().Err(err)
+		return nil
+	}
+	text := l.logs.GetText(true)
+	if text == logMessage || strings.TrimSpace(text) == "" {
+		l.app.Flash().Warn("No logs to explain")
+		return nil
+	}
+	lines := strings.Split(strings.TrimRight(text, "\n"), "\n")
+	stamps := l.model.Timestamps()
+	if len(stamps) != len(lines) {
+		stamps = nil
+	}
+	v := NewClaudeLogs(l.app, l.model.GVR(), l.model.GetPath(), lines, stamps, l.logSource(), l.jumpTo)
+	if err := l.app.inject(v, false); err != nil {
+		l.app.Flash().Err(err)
+	}
+
+	return nil
+}
+
+// logSource describes the logs being viewed.
+func (l *Log) logSource() string {
+	src := l.model.GVR().R() + " " + l.model.GetPath()
+	if co := l.model.GetContainer(); co != "" {
+		src += " container " + co
+	}
+	if l.model.LogOptions().Previous {
+		src += " (previous)"
+	}
+	if q := l.model.GetFilter(); q != "" {
+		src += fmt.Sprintf(", filtered by %q", q)
+	}
+
+	return src
+}
+
+// jumpTo scrolls to a given 1 based line. Auto scroll and text wrap are
+// turned off so the line stays in view and rows match log lines.
+func (l *Log) jumpTo(line int) {
+	if l.indicator.AutoScroll() {
+		l.indicator.ToggleAutoScroll()
+		l.follow = false
+	}
+	if l.indicator.TextWrap() {
+		l.indicator.ToggleTextWrap()
+		l.logs.SetWrap(false)
+	}
+	l.indicator.Refresh()
+	l.logs.ScrollTo(line-1, 0)
+	l.app.Flash().Infof("Jumped to log line %d", line)
+}
+
 func (l *Log) clearCmd(*tcell.EventKey) *tcell.EventKey {
 	l.model.Clear()
 	return nil
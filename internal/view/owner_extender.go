@@ -20,7 +20,6 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
-	"k8s.io/apimachinery/pkg/runtime/schema"
 )
 
 // OwnerExtender adds owner actions to a given viewer.
@@ -95,23 +94,11 @@ func (v *OwnerExtender) findOwnerFor(path string) error {
 }
 
 func (v *OwnerExtender) jumpOwner(ns string, owner *metav1.OwnerReference) error {
-	gv, err := schema.ParseGroupVersion(owner.APIVersion)
+	gvr, ownerFQN, err := dao.MetaAccess.OwnerFor(ns, owner)
 	if err != nil {
 		return err
 	}
 
-	gvr, namespaced, found := dao.MetaAccess.GVK2GVR(gv, owner.Kind)
-	if !found {
-		return errors.Errorf("unsupported GVK: %s/%s", owner.APIVersion, owner.Kind)
-	}
-
-	var ownerFQN string
-	if namespaced {
-		ownerFQN = client.FQN(ns, owner.Name)
-	} else {
-		ownerFQN = owner.Name
-	}
-
 	v.App().gotoResource(gvr.String(), ownerFQN, false, true)
 	return nil
 }
@@ -135,6 +135,13 @@ func init() {
 		Verbs:        []string{"get", "list", "watch", "delete"},
 		Categories:   []string{"k9s"},
 	})
+	dao.MetaAccess.RegisterMeta(client.CsGVR.String(), &metav1.APIResource{
+		Name:         "claudesessions",
+		SingularName: "claudesession",
+		Kind:         "ClaudeSessions",
+		Verbs:        []string{"delete"},
+		Categories:   []string{"k9s"},
+	})
 	dao.MetaAccess.RegisterMeta(client.StsGVR.String(), &metav1.APIResource{
 		Name:         "statefulsets",
 		SingularName: "statefulset",
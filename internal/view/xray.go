@@ -143,6 +143,9 @@ func (x *Xray) refreshActions() {
 		if err := hotKeyActions(x, aa); err != nil {
 			slog.Warn("HotKeys load failed", slogs.Error, err)
 		}
+		if err := promptActions(x, aa); err != nil {
+			slog.Warn("Prompts load failed", slogs.Error, err)
+		}
 
 		x.Actions().Merge(aa)
 		x.app.Menu().HydrateMenu(x.Hints())
@@ -179,6 +182,7 @@ func (x *Xray) refreshActions() {
 			ui.KeyY: ui.NewKeyAction(yamlAction, x.viewCmd, true),
 			ui.KeyD: ui.NewKeyAction("Describe", x.describeCmd, true),
 		})
+		aa.Bulk(askClaudeKey(x.app, x.selectedTarget))
 	}
 
 	switch gvr {
@@ -229,6 +233,15 @@ func (x *Xray) selectedSpec() *xray.NodeSpec {
 	return &ref
 }
 
+func (x *Xray) selectedTarget() (*client.GVR, string) {
+	spec := x.selectedSpec()
+	if spec == nil {
+		return nil, ""
+	}
+
+	return spec.GVR(), spec.Path()
+}
+
 // EnvFn returns an plugin env function if available.
 func (x *Xray) EnvFn() EnvFunc {
 	return x.envFn
@@ -365,7 +378,7 @@ func (x *Xray) viewCmd(evt *tcell.EventKey) *tcell.EventKey {
 		return nil
 	}
 
-	details := NewDetails(x.app, yamlAction, spec.Path(), contentYAML, true).Update(raw)
+	details := NewDetails(x.app, yamlAction, spec.Path(), contentYAML, true).ForResource(spec.GVR()).Update(raw)
 	if err := x.app.inject(details, false); err != nil {
 		x.app.Flash().Err(err)
 	}
@@ -417,7 +430,7 @@ func (x *Xray) describe(gvr *client.GVR, path string) {
 		return
 	}
 
-	details := NewDetails(x.app, "Describe", path, contentYAML, true).Update(yaml)
+	details := NewDetails(x.app, "Describe", path, contentYAML, true).ForResource(gvr).Update(yaml)
 	if err := x.app.inject(details, false); err != nil {
 		x.app.Flash().Err(err)
 	}